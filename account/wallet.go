@@ -17,7 +17,6 @@
 package account
 
 import (
-	"encoding/json"
 	"errors"
 	"fmt"
 	"github.com/Zilliqa/gozilliqa-sdk/bech32"
@@ -28,6 +27,8 @@ import (
 	"github.com/Zilliqa/gozilliqa-sdk/transaction"
 	"github.com/Zilliqa/gozilliqa-sdk/util"
 	"github.com/Zilliqa/gozilliqa-sdk/validator"
+	"github.com/ybbus/jsonrpc"
+	"math"
 	"math/big"
 	"strconv"
 	"strings"
@@ -45,7 +46,7 @@ func NewWallet() *Wallet {
 	}
 }
 
-func (w *Wallet) Sign(tx *transaction.Transaction, provider provider.Provider) error {
+func (w *Wallet) Sign(tx *transaction.Transaction, provider provider.Reader) error {
 	if strings.HasPrefix(tx.ToAddr, "0x") {
 		tx.ToAddr = strings.TrimPrefix(tx.ToAddr, "0x")
 	}
@@ -88,12 +89,18 @@ func (w *Wallet) Sign(tx *transaction.Transaction, provider provider.Provider) e
 
 }
 
-func (w *Wallet) SignWith(tx *transaction.Transaction, signer string, provider provider.Provider) error {
+// SignWith signs tx with the account signer. If the nonce of tx is empty it is looked up with
+// provider; with a nonce set provider may be nil, and nothing is read from the network.
+func (w *Wallet) SignWith(tx *transaction.Transaction, signer string, provider provider.Reader) error {
 	account, ok := w.Accounts[strings.ToUpper(signer)]
 	if !ok {
 		return errors.New("account does not exist")
 	}
 
+	if tx.Nonce == "" && provider == nil {
+		return errors.New("nonce missing, it cannot be looked up without a provider")
+	}
+
 	if tx.Nonce == "" {
 		response, err := provider.GetBalance(signer)
 		if err != nil {
@@ -102,38 +109,26 @@ func (w *Wallet) SignWith(tx *transaction.Transaction, signer string, provider p
 		if response == nil {
 			return errors.New("get balance response err")
 		}
-		if response.Error == nil {
-			result := response.Result.(map[string]interface{})
-			n := result["nonce"].(json.Number)
-			bal := result["balance"].(string)
-			balNumber, ok := new(big.Int).SetString(bal, 10)
-			if !ok {
-				return errors.New("parse balance error")
-			}
-			amount, ok2 := new(big.Int).SetString(tx.Amount, 10)
-			if !ok2 {
-				return errors.New("parse amount error")
-			}
-			gasPrice, ok3 := new(big.Int).SetString(tx.GasPrice, 10)
-			if !ok3 {
-				return errors.New("parse gas price error")
-			}
-			gasLimit, ok4 := new(big.Int).SetString(tx.GasLimit, 10)
-			if !ok4 {
-				return errors.New("parse gas limit error")
+		balance, err := parseBalance(response)
+		switch {
+		case isAccountNotCreated(err):
+			tx.Nonce = "1"
+		case err != nil:
+			return err
+		default:
+			typed, err := tx.Typed()
+			if err != nil {
+				return err
 			}
 
-			gasFee := new(big.Int).Mul(gasPrice, gasLimit)
-			needed := new(big.Int).Add(gasFee, amount)
+			gasFee := new(big.Int).Mul(typed.GasPrice, new(big.Int).SetUint64(typed.GasLimit))
+			needed := new(big.Int).Add(gasFee, typed.Amount)
 
-			if needed.Cmp(balNumber) > 0 {
+			if needed.Cmp(balance.Balance) > 0 {
 				return errors.New("balance is not sufficient")
 			}
 
-			nonce, _ := n.Int64()
-			tx.Nonce = strconv.FormatInt(nonce+1, 10)
-		} else {
-			tx.Nonce = "1"
+			tx.Nonce = strconv.FormatUint(balance.Nonce+1, 10)
 		}
 	}
 
@@ -160,6 +155,58 @@ func (w *Wallet) SignWith(tx *transaction.Transaction, signer string, provider p
 	return nil
 }
 
+// SignOffline signs tx without a provider, for the chain chainID and with the explicit nonce.
+// The version of tx is set for chainID if it is empty, and must be for chainID otherwise.
+// chainID must be between 1 and 65535 and nonce must not be 0; tx is left untouched if not.
+func (w *Wallet) SignOffline(tx *transaction.Transaction, chainID int, nonce uint64) error {
+	if chainID < 1 || chainID > math.MaxUint16 {
+		return fmt.Errorf("invalid chain id %d", chainID)
+	}
+	if nonce == 0 {
+		return errors.New("nonce missing")
+	}
+	if tx.Version == "" {
+		tx.Version = transaction.NewVersion(uint16(chainID), transaction.DefaultMsgVersion).String()
+	}
+	version, err := transaction.ParseVersion(tx.Version)
+	if err != nil {
+		return err
+	}
+	if int(version.ChainID) != chainID {
+		return fmt.Errorf("transaction is for chain %d, not %d", version.ChainID, chainID)
+	}
+	tx.Nonce = strconv.FormatUint(nonce, 10)
+	return w.Sign(tx, nil)
+}
+
+// SignEnvelope signs the transaction of env offline, with the account of its sender, and puts
+// the signature into env. chainID is the chain the signer expects the transaction to be for.
+func (w *Wallet) SignEnvelope(env *transaction.Envelope, chainID int) error {
+	if int(env.ChainID) != chainID {
+		return fmt.Errorf("envelope is for chain %d, not %d", env.ChainID, chainID)
+	}
+	tx, err := env.Tx()
+	if err != nil {
+		return err
+	}
+	signer := keytools.GetAddressFromPublic(util.DecodeHex(tx.SenderPubKey))
+	if err := w.SignWith(tx, signer, nil); err != nil {
+		return err
+	}
+	return env.Sign(tx.Signature)
+}
+
+// isAccountNotCreated reports whether err means that the signer never received funds,
+// in which case its first transaction has nonce 1.
+func isAccountNotCreated(err error) bool {
+	return errors.Is(err, provider.ErrAccountNotCreated)
+}
+
+// parseBalance is provider.ParseBalance, for functions whose provider parameter hides the package.
+func parseBalance(response *jsonrpc.RPCResponse) (*provider.Balance, error) {
+	return provider.ParseBalance(response)
+}
+
 func (w *Wallet) CreateAccount() {
 	privateKey, _ := keytools.GeneratePrivateKey()
 	account := NewAccount(privateKey[:])
@@ -17,11 +17,16 @@
 package account
 
 import (
+	"encoding/json"
 	"fmt"
 	provider2 "github.com/Zilliqa/gozilliqa-sdk/provider"
 	"github.com/Zilliqa/gozilliqa-sdk/transaction"
 	"github.com/Zilliqa/gozilliqa-sdk/util"
+	"github.com/Zilliqa/gozilliqa-sdk/zilliqatest"
 	"github.com/stretchr/testify/assert"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
 	"os"
 	"strconv"
 	"testing"
@@ -40,7 +45,7 @@ func TestWallet_SignWith(t *testing.T) {
 		GasLimit: "1",
 	}
 	provider := provider2.NewProvider("https://dev-api.zilliqa.com/")
-	err := wallet.SignWith(tx, "9bfec715a6bd658fcb62b0f8cc9bfa2ade71434a", *provider)
+	err := wallet.SignWith(tx, "9bfec715a6bd658fcb62b0f8cc9bfa2ade71434a", provider)
 	assert.Nil(t, err, err)
 	assert.NotEmpty(t, tx.Signature)
 }
@@ -69,10 +74,12 @@ func TestSendTransaction(t *testing.T) {
 		Priority:     false,
 	}
 
-	err2 := wallet.Sign(tx, *provider)
+	err2 := wallet.Sign(tx, provider)
 	assert.Nil(t, err2, err2)
 
-	rsp, err3 := provider.CreateTransaction(tx.ToTransactionPayload())
+	payload, err3 := tx.Payload()
+	assert.Nil(t, err3, err3)
+	rsp, err3 := provider.CreateTransaction(payload)
 	assert.Nil(t, err3, err3)
 	assert.Nil(t, rsp.Error, rsp.Error)
 
@@ -83,6 +90,40 @@ func TestSendTransaction(t *testing.T) {
 	assert.True(t, tx.Status == transaction.Confirmed)
 }
 
+func TestSendTransactionOffline(t *testing.T) {
+	server := zilliqatest.NewServer(zilliqatest.WithAutoMine())
+	defer server.Close()
+	server.Fund("9bfec715a6bd658fcb62b0f8cc9bfa2ade71434a", "1000000000000")
+
+	wallet := NewWallet()
+	wallet.AddByPrivateKey("e19d05c5452598e24caad4a0d85a49146f7be089515c905ae6a19e8a578a6930")
+	provider := server.Provider()
+
+	tx := &transaction.Transaction{
+		Version:  strconv.FormatInt(int64(util.Pack(server.ChainID(), 1)), 10),
+		ToAddr:   "4BAF5faDA8e5Db92C3d3242618c5B47133AE003C",
+		Amount:   "10000000",
+		GasPrice: "1000000000",
+		GasLimit: "1",
+	}
+	err := wallet.Sign(tx, provider)
+	assert.Nil(t, err, err)
+	assert.Equal(t, "1", tx.Nonce)
+
+	payload, err := tx.Payload()
+	assert.Nil(t, err, err)
+	result, err := provider.CreateTransactionTyped(payload)
+	assert.Nil(t, err, err)
+	tx.Confirm(result.TranID, 1, 1, provider)
+	assert.True(t, tx.Status == transaction.Confirmed)
+
+	tx.Amount = "10000000000000000"
+	tx.Nonce = ""
+	err = wallet.Sign(tx, provider)
+	assert.NotNil(t, err)
+	assert.Equal(t, err.Error(), "balance is not sufficient")
+}
+
 func TestSendTransactionInsufficientAmount(t *testing.T) {
 	if os.Getenv("CI") != "" {
 		t.Skip("Skipping testing in CI environment")
@@ -107,7 +148,122 @@ func TestSendTransactionInsufficientAmount(t *testing.T) {
 		Priority:     false,
 	}
 
-	err2 := wallet.Sign(tx, *provider)
+	err2 := wallet.Sign(tx, provider)
 	assert.NotNil(t, err2)
 	assert.Equal(t, err2.Error(), "balance is not sufficient")
 }
+
+func TestWallet_SignWithBadBalance(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"id":1,"jsonrpc":"2.0","result":{"balance":100,"nonce":"1"}}`))
+	}))
+	defer server.Close()
+	wallet := NewWallet()
+	wallet.AddByPrivateKey("e19d05c5452598e24caad4a0d85a49146f7be089515c905ae6a19e8a578a6930")
+
+	tx := &transaction.Transaction{
+		Version:  strconv.FormatInt(int64(util.Pack(333, 1)), 10),
+		ToAddr:   "4BAF5faDA8e5Db92C3d3242618c5B47133AE003C",
+		Amount:   "10000000",
+		GasPrice: "1000000000",
+		GasLimit: "50",
+	}
+	err := wallet.Sign(tx, provider2.NewProvider(server.URL))
+	assert.NotNil(t, err)
+	assert.Empty(t, tx.Nonce)
+}
+
+func TestWallet_SignOffline(t *testing.T) {
+	wallet := NewWallet()
+	wallet.AddByPrivateKey("e19d05c5452598e24caad4a0d85a49146f7be089515c905ae6a19e8a578a6930")
+
+	tx := &transaction.Transaction{
+		ToAddr:   "4BAF5faDA8e5Db92C3d3242618c5B47133AE003C",
+		Amount:   "10000000",
+		GasPrice: "1000000000",
+		GasLimit: "50",
+	}
+	err := wallet.SignWith(tx, "9bfec715a6bd658fcb62b0f8cc9bfa2ade71434a", nil)
+	assert.NotNil(t, err)
+
+	err = wallet.SignOffline(tx, 333, 5)
+	assert.Nil(t, err, err)
+	assert.Equal(t, "21823489", tx.Version)
+	assert.Equal(t, "5", tx.Nonce)
+	assert.NotEmpty(t, tx.Signature)
+
+	err = wallet.SignOffline(tx, 1, 5)
+	assert.NotNil(t, err)
+}
+
+func TestWallet_SignOfflineInvalid(t *testing.T) {
+	wallet := NewWallet()
+	wallet.AddByPrivateKey("e19d05c5452598e24caad4a0d85a49146f7be089515c905ae6a19e8a578a6930")
+
+	for _, c := range []struct {
+		chainID int
+		nonce   uint64
+	}{{0, 5}, {-1, 5}, {65536, 5}, {65536 + 333, 5}, {333, 0}} {
+		tx := &transaction.Transaction{
+			ToAddr:   "4BAF5faDA8e5Db92C3d3242618c5B47133AE003C",
+			Amount:   "10000000",
+			GasPrice: "1000000000",
+			GasLimit: "50",
+		}
+		err := wallet.SignOffline(tx, c.chainID, c.nonce)
+		assert.NotNil(t, err, c)
+		assert.Empty(t, tx.Version, c)
+		assert.Empty(t, tx.Nonce, c)
+		assert.Empty(t, tx.Signature, c)
+	}
+}
+
+// TestWallet_SignEnvelope builds a transaction online without keys, signs it offline and
+// broadcasts the signed envelope.
+func TestWallet_SignEnvelope(t *testing.T) {
+	server := zilliqatest.NewServer()
+	defer server.Close()
+	server.Fund("9bfec715a6bd658fcb62b0f8cc9bfa2ade71434a", "10000000000000")
+	provider := server.Provider()
+
+	// online: the public key of the offline account and its nonce are known
+	balance, err := provider.GetBalanceTyped("9bfec715a6bd658fcb62b0f8cc9bfa2ade71434a")
+	assert.Nil(t, err, err)
+	tx, err := transaction.Transfer("4BAF5faDA8e5Db92C3d3242618c5B47133AE003C", big.NewInt(1500000000000)).
+		ForChain(server.ChainID()).
+		WithGasPrice(big.NewInt(1000000000)).
+		WithNonce(uint64(balance.Nonce) + 1).
+		WithPriority(true).
+		Build()
+	assert.Nil(t, err, err)
+	tx.SenderPubKey = "0246E7178DC8253201101E18FD6F6EB9972451D121FC57AA2A06DD5C111E58DC6A"
+	env, err := transaction.NewEnvelope(tx)
+	assert.Nil(t, err, err)
+	unsigned, err := json.Marshal(env)
+	assert.Nil(t, err, err)
+
+	// offline: only the wallet
+	wallet := NewWallet()
+	wallet.AddByPrivateKey("e19d05c5452598e24caad4a0d85a49146f7be089515c905ae6a19e8a578a6930")
+	offline, err := transaction.ParseEnvelope(unsigned)
+	assert.Nil(t, err, err)
+	assert.Equal(t, "1.5", offline.Summary.Amount)
+	assert.NotNil(t, wallet.SignEnvelope(offline, 1))
+	assert.Nil(t, wallet.SignEnvelope(offline, server.ChainID()))
+	signed, err := offline.MarshalBinary()
+	assert.Nil(t, err, err)
+
+	// online again
+	env, err = transaction.ParseEnvelope(signed)
+	assert.Nil(t, err, err)
+	payload, err := env.Payload()
+	assert.Nil(t, err, err)
+	assert.True(t, payload.Priority)
+	result, err := provider.CreateTransactionTyped(payload)
+	assert.Nil(t, err, err)
+	assert.Equal(t, env.Summary.Hash, result.TranID)
+	server.Mine()
+	receipt, err := provider.GetTransactionTyped(result.TranID)
+	assert.Nil(t, err, err)
+	assert.True(t, receipt.Receipt.Success)
+}
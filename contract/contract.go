@@ -41,7 +41,7 @@ type Contract struct {
 	ContractStatus ContractStatus `json:"contractStatus"`
 
 	Signer   *account.Wallet
-	Provider *provider.Provider
+	Provider provider.Client
 }
 
 type Value struct {
@@ -75,12 +75,17 @@ func (c *Contract) Deploy(params DeployParams) (*transaction.Transaction, error)
 		Status:       0,
 	}
 
-	err2 := c.Signer.Sign(tx, *c.Provider)
+	err2 := c.Signer.Sign(tx, c.Provider)
 	if err2 != nil {
 		return nil, err2
 	}
 
-	rsp, err := c.Provider.CreateTransaction(tx.ToTransactionPayload())
+	payload, err := tx.Payload()
+	if err != nil {
+		return nil, err
+	}
+
+	rsp, err := c.Provider.CreateTransaction(payload)
 
 	if err != nil {
 		return nil, err
@@ -130,7 +135,7 @@ func (c *Contract) Sign(transition string, args []Value, params CallParams, prio
 		Priority:     priority,
 	}
 
-	err2 := c.Signer.Sign(tx, *c.Provider)
+	err2 := c.Signer.Sign(tx, c.Provider)
 	if err2 != nil {
 		return err2, nil
 	}
@@ -165,12 +170,17 @@ func (c *Contract) Call(transition string, args []Value, params CallParams, prio
 		Priority:     priority,
 	}
 
-	err2 := c.Signer.Sign(tx, *c.Provider)
+	err2 := c.Signer.Sign(tx, c.Provider)
 	if err2 != nil {
 		return tx, err2
 	}
 
-	rsp, err := c.Provider.CreateTransaction(tx.ToTransactionPayload())
+	payload, err := tx.Payload()
+	if err != nil {
+		return tx, err
+	}
+
+	rsp, err := c.Provider.CreateTransaction(payload)
 
 	if err != nil {
 		return tx, err
@@ -29,6 +29,7 @@ import (
 	"github.com/Zilliqa/gozilliqa-sdk/keytools"
 	provider2 "github.com/Zilliqa/gozilliqa-sdk/provider"
 	"github.com/Zilliqa/gozilliqa-sdk/util"
+	"github.com/Zilliqa/gozilliqa-sdk/zilliqatest"
 )
 
 func TestContract_Deploy(t *testing.T) {
@@ -109,6 +110,44 @@ func TestContract_Deploy(t *testing.T) {
 	assert.True(t, tx.Status == transaction.Confirmed)
 }
 
+func TestContract_DeployOffline(t *testing.T) {
+	server := zilliqatest.NewServer(zilliqatest.WithAutoMine())
+	defer server.Close()
+	privateKey := "e19d05c5452598e24caad4a0d85a49146f7be089515c905ae6a19e8a578a6930"
+	publicKey := keytools.GetPublicKeyFromPrivateKey(util.DecodeHex(privateKey), true)
+	address := keytools.GetAddressFromPublic(publicKey)
+	server.Fund(address, "1000000000000000")
+	provider := server.Provider()
+
+	wallet := account.NewWallet()
+	wallet.AddByPrivateKey(privateKey)
+	code, _ := ioutil.ReadFile("./fungible.scilla")
+	contract := Contract{
+		Code: string(code),
+		Init: []Value{
+			{"_scilla_version", "Uint32", "0"},
+			{"owner", "ByStr20", "0x" + address},
+		},
+		Signer:   wallet,
+		Provider: provider,
+	}
+
+	tx, err := contract.Deploy(DeployParams{
+		Version:      strconv.FormatInt(int64(util.Pack(server.ChainID(), 1)), 10),
+		GasPrice:     "1000000000",
+		GasLimit:     "10000",
+		SenderPubKey: util.EncodeHex(publicKey),
+	})
+	assert.Nil(t, err, err)
+	tx.Confirm(tx.ID, 1, 1, provider)
+	assert.True(t, tx.Status == transaction.Confirmed)
+	assert.Equal(t, GetAddressFromContract(tx), tx.ContractAddress)
+
+	deployed, err := provider.GetSmartContractCodeTyped(tx.ContractAddress)
+	assert.Nil(t, err, err)
+	assert.Equal(t, string(code), deployed)
+}
+
 func TestContract_Call(t *testing.T) {
 	if os.Getenv("CI") != "" {
 		t.Skip("Skipping testing in CI environment")
@@ -227,7 +266,8 @@ func TestContract_Sign(t *testing.T) {
 	err, tx := contract.Sign("SubmitCustomMintTransaction", args, params, true)
 	assert.Nil(t, err, err)
 
-	pl := tx.ToTransactionPayload()
+	pl, err := tx.Payload()
+	assert.Nil(t, err, err)
 	j, _ := pl.ToJson()
 
 	_, err2 := provider2.NewFromJson(j)
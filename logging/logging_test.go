@@ -0,0 +1,43 @@
+/*
+ * Copyright (C) 2019 Zilliqa
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+package logging
+
+import (
+	"bytes"
+	"github.com/stretchr/testify/assert"
+	"strings"
+	"testing"
+)
+
+func TestTextLogger(t *testing.T) {
+	var buf bytes.Buffer
+	logger := NewTextLogger(&buf, LevelInfo)
+	logger.Debug("dropped")
+	logger.Info("confirmed", "hash", "abc", "attempts", 2)
+	logger.Error("odd", "key")
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	assert.Equal(t, 2, len(lines))
+	assert.True(t, strings.HasSuffix(lines[0], " info confirmed hash=abc attempts=2"), lines[0])
+	assert.True(t, strings.HasSuffix(lines[1], " error odd key=(missing)"), lines[1])
+}
+
+func TestOrNop(t *testing.T) {
+	assert.Equal(t, Nop, OrNop(nil))
+	logger := NewTextLogger(&bytes.Buffer{}, LevelDebug)
+	assert.Equal(t, logger, OrNop(logger))
+}
@@ -0,0 +1,317 @@
+/*
+ * Copyright (C) 2019 Zilliqa
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/ybbus/jsonrpc"
+	"sync"
+	"sync/atomic"
+)
+
+// DefaultMaxBatchSize is the number of calls sent in a single JSON-RPC batch array
+// unless the provider or the batch is configured otherwise.
+const DefaultMaxBatchSize = 100
+
+// ResultParser decodes the raw response of one call into its typed result.
+type ResultParser func(rpcResult *jsonrpc.RPCResponse) (interface{}, error)
+
+// Batch collects RPC calls and sends them to the node as JSON-RPC batch arrays,
+// so that many calls take one round trip per MaxSize calls instead of one each.
+//
+//	results, err := provider.NewBatch().GetTransaction(h1).GetTransaction(h2).Send()
+type Batch struct {
+	provider *Provider
+	maxSize  int
+	calls    []*batchCall
+}
+
+type batchCall struct {
+	method string
+	params []interface{}
+	parse  ResultParser
+}
+
+// BatchResult is the outcome of one call of a batch. Result holds the typed result returned
+// by the call's parser, e.g. *TransactionResult for GetTransaction; Err is set instead if
+// the call failed, either on its own or because its part of the batch could not be sent.
+type BatchResult struct {
+	Method   string
+	Params   []interface{}
+	Response *jsonrpc.RPCResponse
+	Result   interface{}
+	Err      error
+}
+
+func (provider *Provider) NewBatch() *Batch {
+	maxSize := provider.maxBatchSize
+	if maxSize <= 0 {
+		maxSize = DefaultMaxBatchSize
+	}
+	return &Batch{
+		provider: provider,
+		maxSize:  maxSize,
+	}
+}
+
+// WithMaxSize sets the maximum number of calls sent in one HTTP request. Larger batches are
+// split into several requests.
+func (b *Batch) WithMaxSize(maxSize int) *Batch {
+	if maxSize > 0 {
+		b.maxSize = maxSize
+	}
+	return b
+}
+
+// Add queues a call to method. parse decodes its response; if it is nil, Result is left empty
+// and only Response is filled in.
+func (b *Batch) Add(method string, parse ResultParser, params ...interface{}) *Batch {
+	b.calls = append(b.calls, &batchCall{
+		method: method,
+		params: params,
+		parse:  parse,
+	})
+	return b
+}
+
+func (b *Batch) Len() int {
+	return len(b.calls)
+}
+
+func (b *Batch) GetTransaction(transactionHash string) *Batch {
+	return b.Add("GetTransaction", func(rsp *jsonrpc.RPCResponse) (interface{}, error) {
+		return ParseTransaction(rsp)
+	}, transactionHash)
+}
+
+func (b *Batch) GetTxBlock(blockNumber string) *Batch {
+	return b.Add("GetTxBlock", func(rsp *jsonrpc.RPCResponse) (interface{}, error) {
+		return ParseTxBlock(rsp)
+	}, blockNumber)
+}
+
+func (b *Batch) GetDsBlock(blockNumber string) *Batch {
+	return b.Add("GetDsBlock", func(rsp *jsonrpc.RPCResponse) (interface{}, error) {
+		return ParseDsBlock(rsp)
+	}, blockNumber)
+}
+
+func (b *Batch) GetTransactionsForTxBlock(txBlockNumber string) *Batch {
+	return b.Add("GetTransactionsForTxBlock", func(rsp *jsonrpc.RPCResponse) (interface{}, error) {
+		return ParseTxHashArray(rsp)
+	}, txBlockNumber)
+}
+
+func (b *Batch) GetBalance(userAddress string) *Batch {
+	return b.Add("GetBalance", func(rsp *jsonrpc.RPCResponse) (interface{}, error) {
+		return ParseBalance(rsp)
+	}, userAddress)
+}
+
+func (b *Batch) GetPendingTxn(tx string) *Batch {
+	return b.Add("GetPendingTxn", func(rsp *jsonrpc.RPCResponse) (interface{}, error) {
+		return ParsePendingTxn(rsp)
+	}, tx)
+}
+
+func (b *Batch) GetSmartContractState(contractAddress string) *Batch {
+	return b.Add("GetSmartContractState", func(rsp *jsonrpc.RPCResponse) (interface{}, error) {
+		return ParseSmartContractState(rsp)
+	}, contractAddress)
+}
+
+func (b *Batch) GetSmartContractInit(contractAddress string) *Batch {
+	return b.Add("GetSmartContractInit", func(rsp *jsonrpc.RPCResponse) (interface{}, error) {
+		return ParseGetContractInit(rsp)
+	}, contractAddress)
+}
+
+func (b *Batch) GetSmartContractCode(contractAddress string) *Batch {
+	return b.Add("GetSmartContractCode", func(rsp *jsonrpc.RPCResponse) (interface{}, error) {
+		return ParseGetContractCode(rsp)
+	}, contractAddress)
+}
+
+// Send posts the queued calls and returns one result per call, in the order the calls were
+// added. Responses are matched to calls by id. The returned error is the first failure to send
+// a part of the batch; results of the calls in that part carry the same error.
+func (b *Batch) Send() ([]*BatchResult, error) {
+	if len(b.calls) == 0 {
+		return nil, errors.New("empty batch")
+	}
+
+	results := make([]*BatchResult, len(b.calls))
+	for i, c := range b.calls {
+		results[i] = &BatchResult{Method: c.method, Params: c.params}
+	}
+
+	var sendErr error
+	for start := 0; start < len(b.calls); start += b.maxSize {
+		end := start + b.maxSize
+		if end > len(b.calls) {
+			end = len(b.calls)
+		}
+		if err := b.send(start, end, results); err != nil && sendErr == nil {
+			sendErr = err
+		}
+	}
+	return results, sendErr
+}
+
+// batchItem is a call of a batch that went through the interceptors and waits to be sent.
+type batchItem struct {
+	index   int
+	call    *Call
+	outcome chan error
+}
+
+// send sends the calls start to end. Each call first goes through the interceptors on its own,
+// like a single call; the calls reaching the end of the chain are then sent together.
+func (b *Batch) send(start, end int, results []*BatchResult) error {
+	ready := make(chan *batchItem, end-start)
+	calls := make([]*Call, end-start)
+	errs := make([]error, end-start)
+	var wg sync.WaitGroup
+	for i := start; i < end; i++ {
+		calls[i-start] = &Call{Method: b.calls[i].method, Params: b.calls[i].params}
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			var joined int32
+			errs[i-start] = b.provider.intercept(calls[i-start], func(ctx context.Context, call *Call) error {
+				if !atomic.CompareAndSwapInt32(&joined, 0, 1) {
+					// an interceptor calling next again, e.g. to retry, sends the call on its own
+					return b.provider.invoke(ctx, call)
+				}
+				item := &batchItem{index: i, call: call, outcome: make(chan error, 1)}
+				ready <- item
+				return <-item.outcome
+			})
+			// the call was answered by an interceptor without reaching the batch
+			if atomic.CompareAndSwapInt32(&joined, 0, 1) {
+				ready <- nil
+			}
+		}(i)
+	}
+
+	items := make([]*batchItem, 0, end-start)
+	for range calls {
+		if item := <-ready; item != nil {
+			items = append(items, item)
+		}
+	}
+	sendErr := b.sendItems(items)
+	wg.Wait()
+
+	for i := start; i < end; i++ {
+		call, err := calls[i-start], errs[i-start]
+		if err != nil {
+			results[i].Response, results[i].Err = call.Response, err
+			continue
+		}
+		if call.Response == nil {
+			results[i].Err = fmt.Errorf("rpc batch call %s(): no response", call.Method)
+			continue
+		}
+		b.finish(i, call.Response, results)
+	}
+	return sendErr
+}
+
+// sendItems answers the items from the cache if their method is immutable and sends the others
+// as one JSON-RPC batch. It returns the error of sending the batch, which every item sent fails with.
+func (b *Batch) sendItems(items []*batchItem) error {
+	requests := make([]*jsonrpc.RPCRequest, 0, len(items))
+	byIndex := make(map[int]*batchItem, len(items))
+	// the batch is only retried if every call in it may be sent twice, and it is
+	// limited by the limit of every class of call in it
+	idempotent := true
+	classes := make([]MethodClass, 0, len(items))
+	for _, item := range items {
+		request := jsonrpc.NewRequest(item.call.Method, item.call.Params)
+		request.ID = item.index
+		info := lookupMethod(item.call.Method)
+		if info.immutable && b.provider.cache != nil {
+			if response, ok := b.provider.cache.get(request); ok {
+				item.call.Response, item.call.Cached = response, true
+				item.outcome <- nil
+				continue
+			}
+		}
+		requests = append(requests, request)
+		byIndex[item.index] = item
+		idempotent = idempotent && info.idempotent
+		classes = append(classes, info.class)
+	}
+	if len(requests) == 0 {
+		return nil
+	}
+
+	var responses []*jsonrpc.RPCResponse
+	err := b.provider.do(b.provider.Context(), idempotent, classes, func(ctx context.Context, t *transport) error {
+		var err error
+		responses, err = t.batchCall(ctx, requests)
+		return err
+	})
+	if err != nil {
+		for _, item := range byIndex {
+			item.outcome <- err
+		}
+		return err
+	}
+
+	byID := make(map[int]*jsonrpc.RPCResponse, len(responses))
+	for _, response := range responses {
+		if response != nil {
+			byID[response.ID] = response
+		}
+	}
+	for _, request := range requests {
+		item := byIndex[request.ID]
+		response, ok := byID[request.ID]
+		if !ok {
+			item.outcome <- fmt.Errorf("rpc batch call %s(): no response for id %d", request.Method, request.ID)
+			continue
+		}
+		// the sizes are those of the call's own request and response in the batch
+		if body, err := json.Marshal(request); err == nil {
+			item.call.RequestSize = len(body)
+		}
+		if body, err := json.Marshal(response); err == nil {
+			item.call.ResponseSize = len(body)
+		}
+		item.call.Response = response
+		if response.Error == nil && lookupMethod(request.Method).immutable && b.provider.cache != nil {
+			b.provider.cache.set(request, response)
+		}
+		item.outcome <- nil
+	}
+	return nil
+}
+
+// finish fills in the result of call i from its response.
+func (b *Batch) finish(i int, response *jsonrpc.RPCResponse, results []*BatchResult) {
+	results[i].Response = response
+	if b.calls[i].parse != nil {
+		results[i].Result, results[i].Err = b.calls[i].parse(response)
+	} else if response.Error != nil {
+		results[i].Err = NewRPCError(response.Error)
+	}
+}
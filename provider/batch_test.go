@@ -0,0 +1,132 @@
+/*
+ * Copyright (C) 2019 Zilliqa
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+package provider
+
+import (
+	"encoding/json"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// batchServer answers GetTransaction batches in reverse order and reports a missing
+// transaction for the hash "unknown".
+func batchServer(t *testing.T, requestCount *int) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		*requestCount++
+		var requests []struct {
+			ID     int      `json:"id"`
+			Method string   `json:"method"`
+			Params []string `json:"params"`
+		}
+		err := json.NewDecoder(r.Body).Decode(&requests)
+		assert.Nil(t, err, err)
+
+		responses := make([]map[string]interface{}, 0)
+		for i := len(requests) - 1; i >= 0; i-- {
+			req := requests[i]
+			rsp := map[string]interface{}{"id": req.ID, "jsonrpc": "2.0"}
+			if req.Params[0] == "unknown" {
+				rsp["error"] = map[string]interface{}{"code": -20, "message": "Txn Hash not Present"}
+			} else {
+				rsp["result"] = map[string]interface{}{"ID": req.Params[0], "receipt": map[string]interface{}{"success": true}}
+			}
+			responses = append(responses, rsp)
+		}
+		_ = json.NewEncoder(w).Encode(responses)
+	}))
+}
+
+func TestBatch_Send(t *testing.T) {
+	requestCount := 0
+	server := batchServer(t, &requestCount)
+	defer server.Close()
+
+	provider := NewProvider(server.URL)
+	results, err := provider.NewBatch().GetTransaction("a").GetTransaction("unknown").GetTransaction("c").Send()
+	assert.Nil(t, err, err)
+	assert.Equal(t, 1, requestCount)
+	assert.Equal(t, 3, len(results))
+
+	assert.Nil(t, results[0].Err)
+	assert.Equal(t, "a", results[0].Result.(*TransactionResult).ID)
+	assert.NotNil(t, results[1].Err)
+	assert.Nil(t, results[1].Result)
+	assert.Equal(t, "c", results[2].Result.(*TransactionResult).ID)
+}
+
+func TestBatch_WithMaxSize(t *testing.T) {
+	requestCount := 0
+	server := batchServer(t, &requestCount)
+	defer server.Close()
+
+	batch := NewProvider(server.URL).NewBatch().WithMaxSize(2)
+	hashes := []string{"a", "b", "c", "d", "e"}
+	for _, hash := range hashes {
+		batch.GetTransaction(hash)
+	}
+	results, err := batch.Send()
+	assert.Nil(t, err, err)
+	assert.Equal(t, 3, requestCount)
+	for i, hash := range hashes {
+		assert.Equal(t, hash, results[i].Result.(*TransactionResult).ID)
+	}
+
+	_, err = NewProvider(server.URL).NewBatch().Send()
+	assert.NotNil(t, err)
+}
+
+func TestBatch_SendError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"id":0,"jsonrpc":"2.0","error":{"code":-32600,"message":"INVALID_REQUEST"}}`))
+	}))
+	defer server.Close()
+
+	results, err := NewProvider(server.URL).NewBatch().GetTransaction("a").Send()
+	assert.NotNil(t, err)
+	assert.Equal(t, err, results[0].Err)
+}
+
+func TestBatch_WithCache(t *testing.T) {
+	requestCount := 0
+	server := batchServer(t, &requestCount)
+	defer server.Close()
+	provider := NewProviderWithOptions(server.URL, WithCache(NewLRUCache(10)))
+
+	results, err := provider.NewBatch().GetTransaction("a").GetTransaction("unknown").Send()
+	assert.Nil(t, err, err)
+	assert.Equal(t, 1, requestCount)
+	assert.NotNil(t, results[1].Err)
+
+	// a is answered from the cache, the error for unknown is not cached
+	results, err = provider.NewBatch().GetTransaction("a").GetTransaction("unknown").Send()
+	assert.Nil(t, err, err)
+	assert.Equal(t, 2, requestCount)
+	assert.Equal(t, "a", results[0].Result.(*TransactionResult).ID)
+	assert.NotNil(t, results[1].Err)
+
+	// nothing is sent if every call is cached, also for calls made outside a batch
+	results, err = provider.NewBatch().GetTransaction("a").Send()
+	assert.Nil(t, err, err)
+	assert.Equal(t, "a", results[0].Result.(*TransactionResult).ID)
+	tx, err := provider.GetTransactionTyped("a")
+	assert.Nil(t, err, err)
+	assert.Equal(t, "a", tx.ID)
+	assert.Equal(t, 2, requestCount)
+	assert.Equal(t, CacheStats{Hits: 3, Misses: 3}, provider.CacheStats())
+}
@@ -1,5 +1,11 @@
 package provider
 
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+)
+
 type TxBlock struct {
 	Header *Header `json:"header"`
 	Body   *Body   `json:"body"`
@@ -33,3 +39,137 @@ type MicroBlockInfo struct {
 	MicroBlockShardId     uint32
 	MicroBlockTxnRootHash string
 }
+
+type DsBlock struct {
+	Header    *DsBlockHeader `json:"header"`
+	Signature string         `json:"signature"`
+	// B1, B2, CS1 and CS2 are the collective signatures of the DS committee over the block,
+	// only returned by GetDSBlockVerbose.
+	B1  []bool `json:"B1,omitempty"`
+	B2  []bool `json:"B2,omitempty"`
+	CS1 string `json:"CS1,omitempty"`
+	CS2 string `json:"CS2,omitempty"`
+}
+
+// DsBlockHeader is the header of a DS block. The fields marked omitempty are only returned by
+// GetDSBlockVerbose.
+type DsBlockHeader struct {
+	BlockNum       string
+	CommitteeHash  string `json:",omitempty"`
+	Difficulty     uint32
+	DifficultyDS   uint32
+	EpochNum       string `json:",omitempty"`
+	GasPrice       string
+	LeaderPubKey   string
+	MembersEjected []string `json:",omitempty"`
+	PoWWinners     []string
+	PoWWinnersIP   []*PoWWinnerIP `json:",omitempty"`
+	PrevHash       string
+	ReservedField  string  `json:",omitempty"`
+	SWInfo         *SWInfo `json:",omitempty"`
+	ShardingHash   string  `json:",omitempty"`
+	Timestamp      string
+	Version        uint32 `json:",omitempty"`
+}
+
+// PoWWinnerIP is the address of a node that won the proof of work of a DS block.
+type PoWWinnerIP struct {
+	IP   string
+	Port uint32 `json:"port"`
+}
+
+// SWInfo is the version of the node software required from the DS block on.
+type SWInfo struct {
+	Scilla  SWVersion
+	Zilliqa SWVersion
+}
+
+// SWVersion is a software version together with the DS block from which it is required. The
+// node sends it as the array [major, minor, fix, upgrade DS block, commit], with the DS block
+// as a string.
+type SWVersion struct {
+	Major     uint32
+	Minor     uint32
+	Fix       uint32
+	UpgradeDS uint64
+	Commit    uint32
+}
+
+// UnmarshalJSON decodes the array form sent by the node. The upgrade DS block may be a
+// string or a number.
+func (v *SWVersion) UnmarshalJSON(data []byte) error {
+	var fields []json.RawMessage
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return err
+	}
+	if len(fields) != 5 {
+		return fmt.Errorf("software version: expected 5 fields, got %d", len(fields))
+	}
+	numbers := []*uint32{&v.Major, &v.Minor, &v.Fix}
+	for i, n := range numbers {
+		if err := json.Unmarshal(fields[i], n); err != nil {
+			return fmt.Errorf("software version: field %d: %v", i, err)
+		}
+	}
+	var upgradeDS json.Number
+	if err := json.Unmarshal(fields[3], &upgradeDS); err != nil {
+		return fmt.Errorf("software version: upgrade DS block: %v", err)
+	}
+	dsBlock, err := strconv.ParseUint(upgradeDS.String(), 10, 64)
+	if err != nil {
+		return fmt.Errorf("software version: upgrade DS block: %v", err)
+	}
+	v.UpgradeDS = dsBlock
+	if err := json.Unmarshal(fields[4], &v.Commit); err != nil {
+		return fmt.Errorf("software version: commit: %v", err)
+	}
+	return nil
+}
+
+// MarshalJSON encodes the version in the array form sent by the node.
+func (v SWVersion) MarshalJSON() ([]byte, error) {
+	return json.Marshal([]interface{}{v.Major, v.Minor, v.Fix, strconv.FormatUint(v.UpgradeDS, 10), v.Commit})
+}
+
+type DSComm struct {
+	CurrentDSEpoch string
+	CurrentTxEpoch string
+	NumOfDSGuard   uint32
+	DSComm         []string `json:"dscomm"`
+}
+
+// MinerInfo lists the public keys of the DS committee and of the members of each shard
+// at a DS block.
+type MinerInfo struct {
+	DsCommittee []string `json:"dscommittee"`
+	Shards      []*Shard `json:"shards"`
+}
+
+type Shard struct {
+	Nodes []string `json:"nodes"`
+	Size  uint32   `json:"size"`
+}
+
+// ShardingStructure holds the number of peers in each shard.
+type ShardingStructure struct {
+	NumPeers []uint32
+}
+
+// TotalPeers returns the number of peers over all shards.
+func (s *ShardingStructure) TotalPeers() uint64 {
+	var total uint64
+	for _, n := range s.NumPeers {
+		total += uint64(n)
+	}
+	return total
+}
+
+type BlockListing struct {
+	Data     []*BlockListingEntry `json:"data"`
+	MaxPages uint64               `json:"maxPages"`
+}
+
+type BlockListingEntry struct {
+	BlockNum uint64
+	Hash     string
+}
@@ -0,0 +1,118 @@
+/*
+ * Copyright (C) 2019 Zilliqa
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+package provider
+
+import (
+	"encoding/json"
+	"github.com/stretchr/testify/assert"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"sync/atomic"
+	"testing"
+)
+
+// countingServer answers GetTxBlock and GetBalance and counts the requests; block 99
+// does not exist yet.
+func countingServer(t *testing.T, count *int32) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(count, 1)
+		var request struct {
+			Method string   `json:"method"`
+			Params []string `json:"params"`
+		}
+		err := json.NewDecoder(r.Body).Decode(&request)
+		assert.Nil(t, err, err)
+
+		switch {
+		case request.Method == "GetBalance":
+			_, _ = w.Write([]byte(`{"id":1,"jsonrpc":"2.0","result":{"balance":"100","nonce":1}}`))
+		case request.Params[0] == "99":
+			_, _ = w.Write([]byte(`{"id":1,"jsonrpc":"2.0","error":{"code":-1,"message":"Failed to get Tx Block"}}`))
+		default:
+			_, _ = w.Write([]byte(`{"id":1,"jsonrpc":"2.0","result":{"header":{"BlockNum":"` + request.Params[0] + `","NumTxns":2}}}`))
+		}
+	}))
+}
+
+func TestProvider_WithCache(t *testing.T) {
+	var count int32
+	server := countingServer(t, &count)
+	defer server.Close()
+	provider := NewProviderWithOptions(server.URL, WithCache(NewLRUCache(10)))
+
+	for i := 0; i < 3; i++ {
+		block, err := provider.GetTxBlockTyped("5")
+		assert.Nil(t, err, err)
+		assert.Equal(t, "5", block.Header.BlockNum)
+		assert.Equal(t, uint32(2), block.Header.NumTxns)
+	}
+	assert.Equal(t, int32(1), atomic.LoadInt32(&count))
+	assert.Equal(t, CacheStats{Hits: 2, Misses: 1}, provider.CacheStats())
+
+	// balances change, so they are never cached
+	_, _ = provider.GetBalance("addr")
+	_, _ = provider.GetBalance("addr")
+	assert.Equal(t, int32(3), atomic.LoadInt32(&count))
+
+	// neither are errors, the block may be mined later
+	rsp, err := provider.GetTxBlock("99")
+	assert.Nil(t, err, err)
+	assert.NotNil(t, rsp.Error)
+	rsp, err = provider.WithContext(provider.Context()).GetTxBlock("99")
+	assert.Nil(t, err, err)
+	assert.NotNil(t, rsp.Error)
+	assert.Equal(t, int32(5), atomic.LoadInt32(&count))
+	assert.Equal(t, CacheStats{Hits: 2, Misses: 3}, provider.CacheStats())
+	assert.InDelta(t, 0.4, provider.CacheStats().HitRate(), 1e-9)
+}
+
+func TestLRUCache(t *testing.T) {
+	cache := NewLRUCache(2)
+	cache.Set("a", []byte("1"))
+	cache.Set("b", []byte("2"))
+	_, ok := cache.Get("a")
+	assert.True(t, ok)
+	cache.Set("c", []byte("3"))
+
+	_, ok = cache.Get("b")
+	assert.False(t, ok)
+	value, ok := cache.Get("a")
+	assert.True(t, ok)
+	assert.Equal(t, []byte("1"), value)
+	assert.Equal(t, 2, cache.Len())
+}
+
+func TestDiskCache(t *testing.T) {
+	dir, err := ioutil.TempDir("", "cache")
+	assert.Nil(t, err, err)
+	defer os.RemoveAll(dir)
+
+	var count int32
+	server := countingServer(t, &count)
+	defer server.Close()
+
+	for i := 0; i < 2; i++ {
+		cache, err := NewDiskCache(dir)
+		assert.Nil(t, err, err)
+		block, err := NewProviderWithOptions(server.URL, WithCache(cache)).GetTxBlockTyped("7")
+		assert.Nil(t, err, err)
+		assert.Equal(t, "7", block.Header.BlockNum)
+	}
+	assert.Equal(t, int32(1), atomic.LoadInt32(&count))
+}
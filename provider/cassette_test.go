@@ -0,0 +1,66 @@
+/*
+ * Copyright (C) 2019 Zilliqa
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+package provider_test
+
+import (
+	"errors"
+	"github.com/Zilliqa/gozilliqa-sdk/provider"
+	"github.com/Zilliqa/gozilliqa-sdk/zilliqatest"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+// newReplayProvider returns a provider answered by the synthetic cassette in testdata. Its
+// answers are written by hand in the shape of mainnet answers, not recorded, so they only
+// test parsing.
+func newReplayProvider(t *testing.T) *provider.Provider {
+	recorder, err := zilliqatest.NewRecorder("testdata/synthetic.json", zilliqatest.ModeReplay, zilliqatest.WithStrict())
+	assert.Nil(t, err, err)
+	return provider.NewProviderWithOptions("https://api.zilliqa.com/", provider.WithRoundTripper(recorder))
+}
+
+func TestParseTxBlock_Synthetic(t *testing.T) {
+	rsp, err := newReplayProvider(t).GetTxBlock("1664279")
+	assert.Nil(t, err, err)
+	block, err := provider.ParseTxBlock(rsp)
+	assert.Nil(t, err, err)
+	assert.Equal(t, "1664279", block.Header.BlockNum)
+	assert.Equal(t, uint32(4), block.Header.NumMicroBlocks)
+	assert.Equal(t, uint32(1), block.Header.NumTxns)
+	assert.Equal(t, 4, len(block.Body.MicroBlockInfos))
+	assert.Equal(t, uint32(3), block.Body.MicroBlockInfos[3].MicroBlockShardId)
+}
+
+func TestParseTxHashArray_Synthetic(t *testing.T) {
+	p := newReplayProvider(t)
+	hashes, err := p.GetTransactionsForTxBlockTyped("1664279")
+	assert.Nil(t, err, err)
+	assert.Equal(t, 4, len(hashes))
+	assert.Equal(t, []string{"1b5b9ccb3e8d006a5230de9bda23ff91edc794d4f56410560830b418528e446c"}, hashes[3])
+
+	_, err = p.GetTransactionsForTxBlockTyped("1664280")
+	assert.True(t, errors.Is(err, provider.EmptyBlock), err)
+}
+
+func TestParseTransaction_Synthetic(t *testing.T) {
+	tx, err := newReplayProvider(t).GetTransactionTyped("1b5b9ccb3e8d006a5230de9bda23ff91edc794d4f56410560830b418528e446c")
+	assert.Nil(t, err, err)
+	assert.True(t, tx.Receipt.Success)
+	assert.Equal(t, "883", tx.Receipt.CumulativeGas)
+	assert.Equal(t, 1, len(tx.Receipt.EventLogs))
+	assert.Equal(t, 1, len(tx.Receipt.Transitions))
+}
@@ -0,0 +1,68 @@
+/*
+ * Copyright (C) 2019 Zilliqa
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+package provider
+
+import "github.com/ybbus/jsonrpc"
+
+// Reader is the part of the API that reads accounts, blocks and transactions. Wallets read the
+// nonce of the signer through it and transactions are tracked through it.
+type Reader interface {
+	GetNetworkId() (*jsonrpc.RPCResponse, error)
+	GetMinimumGasPrice() (*jsonrpc.RPCResponse, error)
+	GetBalance(userAddress string) (*jsonrpc.RPCResponse, error)
+	GetNumTxBlocks() (*jsonrpc.RPCResponse, error)
+	GetTxBlock(blockNumber string) (*jsonrpc.RPCResponse, error)
+	GetTransaction(transactionHash string) (*jsonrpc.RPCResponse, error)
+	GetTransactionsForTxBlock(txBlockNumber string) (*jsonrpc.RPCResponse, error)
+}
+
+// StatusReader also reads the processing status of transactions that are not final yet.
+type StatusReader interface {
+	Reader
+	GetTransactionStatus(transactionHash string) (*jsonrpc.RPCResponse, error)
+	GetPendingTxn(tx string) (*jsonrpc.RPCResponse, error)
+}
+
+// Sender submits signed transactions to the network.
+type Sender interface {
+	CreateTransaction(payload TransactionPayload) (*jsonrpc.RPCResponse, error)
+}
+
+// ContractReader reads the code, the initialisation parameters and the state of smart contracts.
+type ContractReader interface {
+	GetSmartContractCode(contractAddress string) (*jsonrpc.RPCResponse, error)
+	GetSmartContractInit(contractAddress string) (*jsonrpc.RPCResponse, error)
+	GetSmartContractState(contractAddress string) (*jsonrpc.RPCResponse, error)
+	GetSmartContractSubState(contractAddress string, params ...interface{}) (string, error)
+	GetContractAddressFromTransactionID(transactionId string) (*jsonrpc.RPCResponse, error)
+}
+
+// Client is what the account, contract, transaction and subscription packages need from a node.
+// Provider implements it; mocks, caches or instrumented clients can be used in its place.
+//
+// Calls through an implementation other than Provider are not bound to the context passed to
+// the ...WithContext functions of those packages, which only stop waiting between calls.
+type Client interface {
+	Reader
+	Sender
+	ContractReader
+}
+
+var (
+	_ Client       = (*Provider)(nil)
+	_ StatusReader = (*Provider)(nil)
+)
@@ -0,0 +1,113 @@
+/*
+ * Copyright (C) 2019 Zilliqa
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+package provider
+
+import (
+	"errors"
+	"fmt"
+	"github.com/ybbus/jsonrpc"
+	"strings"
+)
+
+// Classifications of the error responses of the Zilliqa node. An *RPCError matches them with
+// errors.Is, e.g. errors.Is(err, provider.ErrTxnNotFound).
+var (
+	ErrTxnNotFound         = errors.New("transaction not found")
+	ErrNonceTooLow         = errors.New("nonce too low")
+	ErrInsufficientBalance = errors.New("insufficient balance")
+	ErrAccountNotCreated   = errors.New("account is not created")
+	// ErrMethodNotFound is returned by nodes that do not serve a method, e.g. GetTransactionStatus
+	// is only served by some lookup nodes.
+	ErrMethodNotFound = errors.New("method not found")
+	// ErrNotContract is the same error as NotContract, ErrEmptyBlock the same as EmptyBlock.
+	ErrNotContract = NotContract
+	ErrEmptyBlock  = EmptyBlock
+)
+
+// RPCError is an error response of the node. It is returned, possibly wrapped, by the typed
+// methods and the parsers; the raw methods leave it in the Error field of the response.
+type RPCError struct {
+	Code    int
+	Message string
+	Data    interface{}
+}
+
+func NewRPCError(err *jsonrpc.RPCError) *RPCError {
+	return &RPCError{
+		Code:    err.Code,
+		Message: err.Message,
+		Data:    err.Data,
+	}
+}
+
+// ResponseError returns err if it is not nil, or else the error response of rsp as an *RPCError,
+// or nil. It lets callers of the raw methods handle both kinds of failure alike.
+func ResponseError(rsp *jsonrpc.RPCResponse, err error) error {
+	if err != nil {
+		return err
+	}
+	if rsp != nil && rsp.Error != nil {
+		return NewRPCError(rsp.Error)
+	}
+	return nil
+}
+
+func (e *RPCError) Error() string {
+	return fmt.Sprintf("resp code %d, msg %s", e.Code, e.Message)
+}
+
+// The error codes of the node, the same as those of the Bitcoin JSON-RPC server.
+const (
+	codeMiscError        = -1
+	codeInvalidAddress   = -5
+	codeInvalidParameter = -8
+	codeDatabaseError    = -20
+	codeVerifyRejected   = -26
+	codeMethodNotFound   = -32601
+)
+
+// Is reports whether the error falls into one of the classifications declared above. The
+// code decides, the message only tells apart errors sharing a code, e.g. the verification
+// failures of CreateTransaction.
+func (e *RPCError) Is(target error) bool {
+	message := strings.ToLower(e.Message)
+	switch target {
+	case ErrTxnNotFound:
+		return e.Code == codeDatabaseError && strings.Contains(message, "txn hash not present")
+	case ErrNonceTooLow:
+		return e.isVerifyError() && strings.Contains(message, "nonce") &&
+			(strings.Contains(message, "lower") || strings.Contains(message, "too low"))
+	case ErrInsufficientBalance:
+		return e.isVerifyError() && (strings.Contains(message, "insufficient balance") ||
+			strings.Contains(message, "has no balance"))
+	case ErrAccountNotCreated:
+		return e.Code == codeInvalidAddress && strings.Contains(message, "account is not created")
+	case ErrNotContract:
+		return e.Code == codeInvalidAddress && strings.Contains(message, "not contract address")
+	case ErrEmptyBlock:
+		return e.Code == codeMiscError && strings.Contains(message, "txblock has no transactions")
+	case ErrMethodNotFound:
+		return e.Code == codeMethodNotFound
+	}
+	return false
+}
+
+// isVerifyError reports whether the node rejected a transaction, with codeVerifyRejected or
+// codeInvalidParameter depending on the check that failed.
+func (e *RPCError) isVerifyError() bool {
+	return e.Code == codeVerifyRejected || e.Code == codeInvalidParameter
+}
@@ -0,0 +1,78 @@
+/*
+ * Copyright (C) 2019 Zilliqa
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+package provider
+
+import (
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestRPCError_Is(t *testing.T) {
+	cases := []struct {
+		code    int
+		message string
+		target  error
+	}{
+		{-20, "Txn Hash not Present", ErrTxnNotFound},
+		{-26, "Nonce (3) lower than or not equal to the expected nonce (6)", ErrNonceTooLow},
+		{-8, "Nonce (3) lower than current (5)", ErrNonceTooLow},
+		{-26, "Insufficient Balance", ErrInsufficientBalance},
+		{-26, "The sender of the txn has no balance", ErrInsufficientBalance},
+		{-5, "Account is not created", ErrAccountNotCreated},
+		{-5, "Address not contract address", ErrNotContract},
+		{-1, "TxBlock has no transactions", EmptyBlock},
+	}
+	targets := []error{ErrTxnNotFound, ErrNonceTooLow, ErrInsufficientBalance, ErrAccountNotCreated,
+		ErrNotContract, EmptyBlock, ErrMethodNotFound}
+	for _, c := range cases {
+		for _, target := range targets {
+			err := &RPCError{Code: c.code, Message: c.message}
+			assert.Equal(t, target == c.target, errors.Is(err, target), "%s %s", c.message, target)
+		}
+		// the message alone does not classify an error
+		assert.False(t, errors.Is(&RPCError{Code: -32603, Message: c.message}, c.target), c.message)
+	}
+	assert.True(t, errors.Is(&RPCError{Code: -32601, Message: "METHOD_NOT_FOUND: The method being requested is not available on this server"}, ErrMethodNotFound))
+}
+
+func TestRPCError_Returned(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"id":0,"jsonrpc":"2.0","error":{"code":-5,"data":null,"message":"Address not contract address"}}`))
+	}))
+	defer server.Close()
+	provider := NewProvider(server.URL)
+
+	// the raw methods leave the error in the response
+	rsp, err := provider.GetSmartContractInit("9611c53BE6d1b32058b2747bdeCECed7e1216793")
+	assert.Nil(t, err, err)
+	assert.Equal(t, -5, rsp.Error.Code)
+	err = ResponseError(rsp, err)
+	var rpcErr *RPCError
+	assert.True(t, errors.As(err, &rpcErr))
+	assert.Equal(t, -5, rpcErr.Code)
+	assert.True(t, errors.Is(err, ErrNotContract))
+
+	_, err = provider.GetSmartContractInitTyped("9611c53BE6d1b32058b2747bdeCECed7e1216793")
+	assert.Equal(t, NotContract, err)
+
+	_, err = provider.GetBalanceTyped("9611c53BE6d1b32058b2747bdeCECed7e1216793")
+	assert.True(t, errors.As(err, &rpcErr))
+	assert.Equal(t, "ParseBalanceResp: resp code -5, msg Address not contract address", err.Error())
+}
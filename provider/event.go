@@ -0,0 +1,200 @@
+/*
+ * Copyright (C) 2019 Zilliqa
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+package provider
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"strings"
+)
+
+// EventLog is an event emitted by a contract during a transaction.
+type EventLog struct {
+	Address   string  `json:"address"`
+	EventName string  `json:"_eventname"`
+	Params    []Value `json:"params"`
+}
+
+// Param returns the parameter of the event named vname.
+func (e *EventLog) Param(vname string) (*Value, bool) {
+	return findValue(e.Params, vname)
+}
+
+// Transition is a message sent by a contract during a transaction, to another contract or to
+// a user account. Depth is the number of messages that led to it.
+type Transition struct {
+	Addr  string         `json:"addr"`
+	Depth uint           `json:"depth"`
+	Msg   *TransitionMsg `json:"msg"`
+}
+
+// TransitionMsg is the message of a transition. Tag is the transition called on the recipient,
+// empty for plain transfers.
+type TransitionMsg struct {
+	Amount    string  `json:"_amount"`
+	Recipient string  `json:"_recipient"`
+	Tag       string  `json:"_tag"`
+	Params    []Value `json:"params"`
+}
+
+// Param returns the parameter of the message named vname.
+func (m *TransitionMsg) Param(vname string) (*Value, bool) {
+	return findValue(m.Params, vname)
+}
+
+func findValue(values []Value, vname string) (*Value, bool) {
+	for i := range values {
+		if values[i].VName == vname {
+			return &values[i], true
+		}
+	}
+	return nil, false
+}
+
+// UnmarshalJSON decodes the event logs and transitions of the receipt both as raw JSON values,
+// as before, and into Events and TypedTransitions. The typed fields are left empty if they
+// do not have the expected shape.
+func (r *Receipt) UnmarshalJSON(data []byte) error {
+	type raw Receipt
+	if err := json.Unmarshal(data, (*raw)(r)); err != nil {
+		return err
+	}
+	var typed struct {
+		EventLogs   []*EventLog   `json:"event_logs"`
+		Transitions []*Transition `json:"transitions"`
+	}
+	if err := json.Unmarshal(data, &typed); err == nil {
+		r.Events, r.TypedTransitions = typed.EventLogs, typed.Transitions
+	}
+	return nil
+}
+
+// AsBigInt converts a value of type UintN, IntN or BNum, which Scilla encodes as strings.
+func (v *Value) AsBigInt() (*big.Int, error) {
+	if !isIntegerType(v.Type) {
+		return nil, fmt.Errorf("%s: %s is not an integer type", v.VName, v.Type)
+	}
+	var s string
+	switch value := v.Value.(type) {
+	case string:
+		s = value
+	case json.Number:
+		s = value.String()
+	case float64:
+		s = fmt.Sprintf("%.0f", value)
+	default:
+		return nil, fmt.Errorf("%s: unexpected value %v for %s", v.VName, v.Value, v.Type)
+	}
+	n, ok := new(big.Int).SetString(s, 10)
+	if !ok {
+		return nil, fmt.Errorf("%s: invalid %s %q", v.VName, v.Type, s)
+	}
+	return n, nil
+}
+
+// AsString converts a value of type String, ByStr or ByStrN, e.g. the ByStr20 of an address.
+func (v *Value) AsString() (string, error) {
+	if v.Type != "String" && !strings.HasPrefix(v.Type, "ByStr") {
+		return "", fmt.Errorf("%s: %s is not a string type", v.VName, v.Type)
+	}
+	s, ok := v.Value.(string)
+	if !ok {
+		return "", fmt.Errorf("%s: unexpected value %v for %s", v.VName, v.Value, v.Type)
+	}
+	return s, nil
+}
+
+// AsBool converts a value of type Bool, which Scilla encodes as the constructor True or False.
+func (v *Value) AsBool() (bool, error) {
+	if v.Type != "Bool" {
+		return false, fmt.Errorf("%s: %s is not Bool", v.VName, v.Type)
+	}
+	adt, ok := v.Value.(map[string]interface{})
+	if ok {
+		switch adt["constructor"] {
+		case "True":
+			return true, nil
+		case "False":
+			return false, nil
+		}
+	}
+	return false, fmt.Errorf("%s: unexpected value %v for Bool", v.VName, v.Value)
+}
+
+// AsList converts a value of type List T into its elements, each of type T.
+func (v *Value) AsList() ([]Value, error) {
+	elementType, ok := listElementType(v.Type)
+	if !ok {
+		return nil, fmt.Errorf("%s: %s is not a list type", v.VName, v.Type)
+	}
+	elements, ok := v.Value.([]interface{})
+	if !ok {
+		return nil, fmt.Errorf("%s: unexpected value %v for %s", v.VName, v.Value, v.Type)
+	}
+	values := make([]Value, 0, len(elements))
+	for _, element := range elements {
+		values = append(values, Value{VName: v.VName, Type: elementType, Value: element})
+	}
+	return values, nil
+}
+
+// Decode converts a value into the Go type matching its Scilla type: *big.Int for integers
+// and block numbers, string for strings and byte strings, bool for Bool and []interface{} of
+// decoded elements for lists. Values of other types, such as maps and ADTs, are returned as is.
+func (v *Value) Decode() (interface{}, error) {
+	switch {
+	case isIntegerType(v.Type):
+		return v.AsBigInt()
+	case v.Type == "String" || strings.HasPrefix(v.Type, "ByStr"):
+		return v.AsString()
+	case v.Type == "Bool":
+		return v.AsBool()
+	}
+	if _, ok := listElementType(v.Type); ok {
+		elements, err := v.AsList()
+		if err != nil {
+			return nil, err
+		}
+		decoded := make([]interface{}, 0, len(elements))
+		for i := range elements {
+			d, err := elements[i].Decode()
+			if err != nil {
+				return nil, err
+			}
+			decoded = append(decoded, d)
+		}
+		return decoded, nil
+	}
+	return v.Value, nil
+}
+
+func isIntegerType(t string) bool {
+	return t == "BNum" || strings.HasPrefix(t, "Uint") || strings.HasPrefix(t, "Int")
+}
+
+// listElementType returns T for the types List T and List (T).
+func listElementType(t string) (string, bool) {
+	if !strings.HasPrefix(t, "List ") {
+		return "", false
+	}
+	element := strings.TrimSpace(strings.TrimPrefix(t, "List "))
+	if strings.HasPrefix(element, "(") && strings.HasSuffix(element, ")") {
+		element = strings.TrimSpace(element[1 : len(element)-1])
+	}
+	return element, true
+}
@@ -0,0 +1,93 @@
+/*
+ * Copyright (C) 2019 Zilliqa
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+package provider
+
+import (
+	"github.com/stretchr/testify/assert"
+	"math/big"
+	"testing"
+)
+
+func TestParseTransaction_Events(t *testing.T) {
+	rsp := newResponse(t, `{"id":1,"jsonrpc":"2.0","result":{"ID":"abc","receipt":{"cumulative_gas":"1","epoch_num":"1","success":true,
+		"event_logs":[{"_eventname":"TransferSuccess","address":"0x4baf5fada8e5db92c3d3242618c5b47133ae003c","params":[
+			{"type":"ByStr20","value":"0x9bfec715a6bd658fcb62b0f8cc9bfa2ade71434a","vname":"sender"},
+			{"type":"Uint128","value":"340282366920938463463374607431768211455","vname":"amount"}]}],
+		"transitions":[{"addr":"0x4baf5fada8e5db92c3d3242618c5b47133ae003c","depth":0,"msg":{"_amount":"0","_recipient":"0x9bfec715a6bd658fcb62b0f8cc9bfa2ade71434a","_tag":"RecipientAcceptTransfer","params":[
+			{"type":"Bool","value":{"argtypes":[],"arguments":[],"constructor":"True"},"vname":"accepted"}]}}]}}}`)
+	tx, err := ParseTransaction(rsp)
+	assert.Nil(t, err, err)
+
+	// the raw forms are kept
+	assert.Equal(t, 1, len(tx.Receipt.EventLogs))
+	assert.Equal(t, 1, len(tx.Receipt.Transitions))
+
+	event := tx.Receipt.Events[0]
+	assert.Equal(t, "TransferSuccess", event.EventName)
+	sender, ok := event.Param("sender")
+	assert.True(t, ok)
+	address, err := sender.AsString()
+	assert.Nil(t, err, err)
+	assert.Equal(t, "0x9bfec715a6bd658fcb62b0f8cc9bfa2ade71434a", address)
+	amount, _ := event.Param("amount")
+	n, err := amount.AsBigInt()
+	assert.Nil(t, err, err)
+	max, _ := new(big.Int).SetString("340282366920938463463374607431768211455", 10)
+	assert.Equal(t, max, n)
+	_, ok = event.Param("missing")
+	assert.False(t, ok)
+
+	transition := tx.Receipt.TypedTransitions[0]
+	assert.Equal(t, "RecipientAcceptTransfer", transition.Msg.Tag)
+	accepted, _ := transition.Msg.Param("accepted")
+	b, err := accepted.AsBool()
+	assert.Nil(t, err, err)
+	assert.True(t, b)
+}
+
+func TestValue_Decode(t *testing.T) {
+	cases := []struct {
+		value    Value
+		expected interface{}
+	}{
+		{Value{Type: "Uint32", Value: "7"}, big.NewInt(7)},
+		{Value{Type: "Int256", Value: "-7"}, big.NewInt(-7)},
+		{Value{Type: "BNum", Value: "1664279"}, big.NewInt(1664279)},
+		{Value{Type: "String", Value: "hello"}, "hello"},
+		{Value{Type: "ByStr", Value: "0x01"}, "0x01"},
+		{Value{Type: "Bool", Value: map[string]interface{}{"constructor": "False"}}, false},
+		{Value{Type: "List (Uint32)", Value: []interface{}{"1", "2"}}, []interface{}{big.NewInt(1), big.NewInt(2)}},
+		{Value{Type: "List (List ByStr20)", Value: []interface{}{[]interface{}{"0xab"}}}, []interface{}{[]interface{}{"0xab"}}},
+		{Value{Type: "Map ByStr20 Uint128", Value: map[string]interface{}{}}, map[string]interface{}{}},
+	}
+	for _, c := range cases {
+		decoded, err := c.value.Decode()
+		assert.Nil(t, err, err)
+		assert.Equal(t, c.expected, decoded, c.value.Type)
+	}
+}
+
+func TestValue_Errors(t *testing.T) {
+	_, err := (&Value{VName: "to", Type: "ByStr20", Value: "0xab"}).AsBigInt()
+	assert.EqualError(t, err, "to: ByStr20 is not an integer type")
+	_, err = (&Value{VName: "amount", Type: "Uint128", Value: "1.5"}).AsBigInt()
+	assert.EqualError(t, err, `amount: invalid Uint128 "1.5"`)
+	_, err = (&Value{VName: "ok", Type: "Bool", Value: "true"}).AsBool()
+	assert.NotNil(t, err)
+	_, err = (&Value{VName: "list", Type: "List (Uint32)", Value: "1"}).Decode()
+	assert.NotNil(t, err)
+}
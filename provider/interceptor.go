@@ -0,0 +1,225 @@
+/*
+ * Copyright (C) 2019 Zilliqa
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+package provider
+
+import (
+	"context"
+	"github.com/Zilliqa/gozilliqa-sdk/logging"
+	"github.com/ybbus/jsonrpc"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// Call is a single RPC call as seen by interceptors.
+type Call struct {
+	Method string
+	Params []interface{}
+
+	// Response is set once the node answered, also with an error. It stays nil for
+	// GetSmartContractSubState, which returns the raw body.
+	Response *jsonrpc.RPCResponse
+	// Cached is set if the response came from the cache configured by WithCache.
+	Cached bool
+	// RequestSize and ResponseSize are the sizes in bytes of the bodies of the last attempt,
+	// or, for a call of a Batch, of its own request and response in the batch.
+	RequestSize  int
+	ResponseSize int
+}
+
+// Invoker performs a call, retries and failover included, and fills in its response.
+type Invoker func(ctx context.Context, call *Call) error
+
+// Interceptor wraps every call of a provider. It may inspect or change the call and ctx before
+// passing them on to next, and must return the error of next unless it handles it. The calls of
+// a Batch go through the interceptors one by one and are then sent together, with the context
+// of the provider rather than ctx; calling next again sends the call on its own.
+type Interceptor func(ctx context.Context, call *Call, next Invoker) error
+
+// intercept runs invoke through the interceptors of the provider, the first one outermost.
+func (provider *Provider) intercept(call *Call, invoke Invoker) error {
+	for i := len(provider.interceptors) - 1; i >= 0; i-- {
+		interceptor, next := provider.interceptors[i], invoke
+		invoke = func(ctx context.Context, call *Call) error {
+			return interceptor(ctx, call, next)
+		}
+	}
+	return invoke(provider.Context(), call)
+}
+
+// callStatus is "ok", "rpc_error" if the node answered with an error or "error" otherwise.
+func callStatus(call *Call, err error) string {
+	switch {
+	case err != nil:
+		return "error"
+	case call.Response != nil && call.Response.Error != nil:
+		return "rpc_error"
+	default:
+		return "ok"
+	}
+}
+
+// LoggingInterceptor logs every call at debug level, and failed calls at error level, with
+// the method, the duration and the sizes of request and response.
+func LoggingInterceptor(logger logging.Logger) Interceptor {
+	logger = logging.OrNop(logger)
+	return func(ctx context.Context, call *Call, next Invoker) error {
+		start := time.Now()
+		err := next(ctx, call)
+		keyvals := []interface{}{
+			"method", call.Method,
+			"duration", time.Since(start),
+			"request_bytes", call.RequestSize,
+			"response_bytes", call.ResponseSize,
+			"cached", call.Cached,
+		}
+		if err := ResponseError(call.Response, err); err != nil {
+			logger.Error("rpc call failed", append(keyvals, "error", err)...)
+		} else {
+			logger.Debug("rpc call", append(keyvals, "params", call.Params)...)
+		}
+		return err
+	}
+}
+
+// The metrics recorded by MetricsInterceptor. All of them are labelled by method and status,
+// which is "ok", "rpc_error" or "error".
+const (
+	MetricCalls         = "zilliqa_rpc_calls_total"
+	MetricDuration      = "zilliqa_rpc_duration_seconds"
+	MetricRequestBytes  = "zilliqa_rpc_request_bytes"
+	MetricResponseBytes = "zilliqa_rpc_response_bytes"
+)
+
+// MetricsSink receives counters and histogram observations. Labels are given as a map so that
+// they can be passed on to the With method of a Prometheus CounterVec or HistogramVec as is.
+type MetricsSink interface {
+	Count(name string, labels map[string]string, delta float64)
+	Observe(name string, labels map[string]string, value float64)
+}
+
+// MetricsInterceptor counts calls and observes their duration and payload sizes into sink.
+// Calls answered from the cache count as calls, but are not observed.
+func MetricsInterceptor(sink MetricsSink) Interceptor {
+	return func(ctx context.Context, call *Call, next Invoker) error {
+		start := time.Now()
+		err := next(ctx, call)
+		labels := map[string]string{"method": call.Method, "status": callStatus(call, err)}
+		sink.Count(MetricCalls, labels, 1)
+		if !call.Cached {
+			sink.Observe(MetricDuration, labels, time.Since(start).Seconds())
+			sink.Observe(MetricRequestBytes, labels, float64(call.RequestSize))
+			sink.Observe(MetricResponseBytes, labels, float64(call.ResponseSize))
+		}
+		return err
+	}
+}
+
+// DefaultLatencyBuckets are the upper bounds, in seconds, of the latency histograms of
+// MemoryMetrics, the same as the default buckets of the Prometheus client.
+var DefaultLatencyBuckets = []float64{.005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5, 10}
+
+// MemoryMetrics is a MetricsSink keeping counters and histograms in memory, for tests and for
+// applications without a metrics system.
+type MemoryMetrics struct {
+	mu         sync.Mutex
+	buckets    []float64
+	counters   map[string]float64
+	histograms map[string]*Histogram
+}
+
+// Histogram counts observations per bucket. Counts[i] is the number of observations not larger
+// than Buckets[i]; the last count is for the observations above every bucket.
+type Histogram struct {
+	Buckets []float64
+	Counts  []uint64
+	Count   uint64
+	Sum     float64
+}
+
+// NewMemoryMetrics creates a MemoryMetrics whose histograms have the given bucket upper bounds,
+// or DefaultLatencyBuckets if none are given.
+func NewMemoryMetrics(buckets ...float64) *MemoryMetrics {
+	if len(buckets) == 0 {
+		buckets = DefaultLatencyBuckets
+	}
+	buckets = append([]float64(nil), buckets...)
+	sort.Float64s(buckets)
+	return &MemoryMetrics{
+		buckets:    buckets,
+		counters:   make(map[string]float64),
+		histograms: make(map[string]*Histogram),
+	}
+}
+
+// metricKey identifies a metric by name and labels, e.g. name{method="GetBalance",status="ok"}.
+func metricKey(name string, labels map[string]string) string {
+	keys := make([]string, 0, len(labels))
+	for k := range labels {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	key := name + "{"
+	for i, k := range keys {
+		if i > 0 {
+			key += ","
+		}
+		key += k + "=" + strconv.Quote(labels[k])
+	}
+	return key + "}"
+}
+
+func (m *MemoryMetrics) Count(name string, labels map[string]string, delta float64) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.counters[metricKey(name, labels)] += delta
+}
+
+func (m *MemoryMetrics) Observe(name string, labels map[string]string, value float64) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	key := metricKey(name, labels)
+	h, ok := m.histograms[key]
+	if !ok {
+		h = &Histogram{Buckets: m.buckets, Counts: make([]uint64, len(m.buckets)+1)}
+		m.histograms[key] = h
+	}
+	h.Counts[sort.SearchFloat64s(m.buckets, value)]++
+	h.Count++
+	h.Sum += value
+}
+
+// Counter returns the value of a counter.
+func (m *MemoryMetrics) Counter(name string, labels map[string]string) float64 {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.counters[metricKey(name, labels)]
+}
+
+// Histogram returns a copy of a histogram, or nil if nothing was observed into it.
+func (m *MemoryMetrics) Histogram(name string, labels map[string]string) *Histogram {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	h, ok := m.histograms[metricKey(name, labels)]
+	if !ok {
+		return nil
+	}
+	c := *h
+	c.Counts = append([]uint64(nil), h.Counts...)
+	return &c
+}
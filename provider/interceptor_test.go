@@ -0,0 +1,174 @@
+/*
+ * Copyright (C) 2019 Zilliqa
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+package provider
+
+import (
+	"bytes"
+	"context"
+	"github.com/Zilliqa/gozilliqa-sdk/logging"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+)
+
+func TestProvider_WithInterceptors(t *testing.T) {
+	var count int32
+	server := countingServer(t, &count)
+	defer server.Close()
+
+	var order []string
+	tracer := func(name string) Interceptor {
+		return func(ctx context.Context, call *Call, next Invoker) error {
+			order = append(order, name+" "+call.Method)
+			err := next(ctx, call)
+			order = append(order, name+" done")
+			return err
+		}
+	}
+	provider := NewProviderWithOptions(server.URL, WithInterceptors(tracer("outer"), tracer("inner")))
+	_, err := provider.GetBalance("addr")
+	assert.Nil(t, err, err)
+	assert.Equal(t, []string{"outer GetBalance", "inner GetBalance", "inner done", "outer done"}, order)
+}
+
+func TestProvider_InterceptorShortCircuit(t *testing.T) {
+	var count int32
+	server := countingServer(t, &count)
+	defer server.Close()
+
+	provider := NewProviderWithOptions(server.URL, WithInterceptors(func(ctx context.Context, call *Call, next Invoker) error {
+		if call.Method == "GetBalance" {
+			return context.Canceled
+		}
+		return next(ctx, call)
+	}))
+	_, err := provider.GetBalance("addr")
+	assert.Equal(t, context.Canceled, err)
+	assert.Equal(t, int32(0), atomic.LoadInt32(&count))
+
+	_, err = provider.GetTxBlock("1")
+	assert.Nil(t, err, err)
+	assert.Equal(t, int32(1), atomic.LoadInt32(&count))
+}
+
+func TestLoggingInterceptor(t *testing.T) {
+	var count int32
+	server := countingServer(t, &count)
+	defer server.Close()
+
+	var buf bytes.Buffer
+	provider := NewProviderWithOptions(server.URL, WithInterceptors(LoggingInterceptor(logging.NewTextLogger(&buf, logging.LevelDebug))))
+	_, _ = provider.GetTxBlock("1")
+	_, _ = provider.GetTxBlock("99")
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	assert.Equal(t, 2, len(lines))
+	assert.Contains(t, lines[0], "debug rpc call method=GetTxBlock")
+	assert.Contains(t, lines[0], "params=[1]")
+	assert.Contains(t, lines[1], "error rpc call failed method=GetTxBlock")
+	assert.Contains(t, lines[1], "error=resp code -1, msg Failed to get Tx Block")
+}
+
+func TestMetricsInterceptor(t *testing.T) {
+	var count int32
+	server := countingServer(t, &count)
+	defer server.Close()
+
+	metrics := NewMemoryMetrics()
+	provider := NewProviderWithOptions(server.URL, WithCache(NewLRUCache(10)), WithInterceptors(MetricsInterceptor(metrics)))
+	_, _ = provider.GetTxBlock("1")
+	_, _ = provider.GetTxBlock("1")
+	_, _ = provider.GetTxBlock("99")
+	_, _ = NewProviderWithOptions("http://127.0.0.1:1", WithInterceptors(MetricsInterceptor(metrics))).GetTxBlock("1")
+
+	ok := map[string]string{"method": "GetTxBlock", "status": "ok"}
+	assert.Equal(t, float64(2), metrics.Counter(MetricCalls, ok))
+	assert.Equal(t, float64(1), metrics.Counter(MetricCalls, map[string]string{"method": "GetTxBlock", "status": "rpc_error"}))
+	assert.Equal(t, float64(1), metrics.Counter(MetricCalls, map[string]string{"method": "GetTxBlock", "status": "error"}))
+
+	// the cached call is not observed
+	latency := metrics.Histogram(MetricDuration, ok)
+	assert.Equal(t, uint64(1), latency.Count)
+	assert.Equal(t, len(DefaultLatencyBuckets)+1, len(latency.Counts))
+	size := metrics.Histogram(MetricResponseBytes, ok)
+	assert.True(t, size.Sum > 0)
+	assert.Nil(t, metrics.Histogram(MetricDuration, map[string]string{"method": "GetBalance", "status": "ok"}))
+}
+
+func TestMetricsInterceptor_SubState(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"id":"1","jsonrpc":"2.0","result":{"balances":{}}}`))
+	}))
+	defer server.Close()
+
+	metrics := NewMemoryMetrics(10, 100)
+	provider := NewProviderWithOptions(server.URL, WithInterceptors(MetricsInterceptor(metrics)))
+	_, err := provider.GetSmartContractSubState("addr", "balances", []string{})
+	assert.Nil(t, err, err)
+
+	size := metrics.Histogram(MetricResponseBytes, map[string]string{"method": "GetSmartContractSubState", "status": "ok"})
+	assert.Equal(t, []uint64{0, 1, 0}, size.Counts)
+}
+
+func TestMetricsInterceptor_Batch(t *testing.T) {
+	requestCount := 0
+	server := batchServer(t, &requestCount)
+	defer server.Close()
+
+	metrics := NewMemoryMetrics()
+	provider := NewProviderWithOptions(server.URL, WithCache(NewLRUCache(10)), WithInterceptors(MetricsInterceptor(metrics)))
+	_, err := provider.NewBatch().GetTransaction("a").GetTransaction("unknown").GetTransaction("c").Send()
+	assert.Nil(t, err, err)
+	_, err = provider.NewBatch().GetTransaction("a").Send()
+	assert.Nil(t, err, err)
+	assert.Equal(t, 1, requestCount)
+
+	ok := map[string]string{"method": "GetTransaction", "status": "ok"}
+	assert.Equal(t, float64(3), metrics.Counter(MetricCalls, ok))
+	assert.Equal(t, float64(1), metrics.Counter(MetricCalls, map[string]string{"method": "GetTransaction", "status": "rpc_error"}))
+	// the cached call is not observed
+	size := metrics.Histogram(MetricRequestBytes, ok)
+	assert.Equal(t, uint64(2), size.Count)
+	assert.True(t, size.Sum > 0)
+}
+
+func TestBatch_InterceptorShortCircuit(t *testing.T) {
+	requestCount := 0
+	server := batchServer(t, &requestCount)
+	defer server.Close()
+
+	var buf bytes.Buffer
+	provider := NewProviderWithOptions(server.URL, WithInterceptors(
+		LoggingInterceptor(logging.NewTextLogger(&buf, logging.LevelDebug)),
+		func(ctx context.Context, call *Call, next Invoker) error {
+			if call.Params[0] == "b" {
+				return context.Canceled
+			}
+			return next(ctx, call)
+		}))
+	results, err := provider.NewBatch().GetTransaction("a").GetTransaction("b").GetTransaction("c").Send()
+	assert.Nil(t, err, err)
+	assert.Equal(t, 1, requestCount)
+	assert.Equal(t, "a", results[0].Result.(*TransactionResult).ID)
+	assert.Equal(t, context.Canceled, results[1].Err)
+	assert.Equal(t, "c", results[2].Result.(*TransactionResult).ID)
+	assert.Equal(t, 2, strings.Count(buf.String(), "rpc call method=GetTransaction"), buf.String())
+	assert.Equal(t, 1, strings.Count(buf.String(), "rpc call failed"), buf.String())
+}
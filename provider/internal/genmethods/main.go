@@ -0,0 +1,167 @@
+/*
+ * Copyright (C) 2019 Zilliqa
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+// Command genmethods writes provider/methods_gen.go from the declarations in methods.go:
+// the registry the provider uses to decide how to send a call, a raw wrapper returning the
+// *jsonrpc.RPCResponse and, where a result type is declared, a typed wrapper for every method.
+//
+// Run it through go generate in the provider directory after adding or changing a declaration.
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"go/format"
+	"io/ioutil"
+	"os"
+	"strings"
+	"text/template"
+)
+
+func main() {
+	output := flag.String("o", "methods_gen.go", "output file")
+	flag.Parse()
+
+	source, err := generate()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	if err := ioutil.WriteFile(*output, source, 0644); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
+
+// generate renders the declared methods into the formatted source of methods_gen.go.
+func generate() ([]byte, error) {
+	for _, m := range methods {
+		if m.Func == "" {
+			m.Func = m.Name
+		}
+		if m.Class == "" {
+			m.Class = "ClassRead"
+		}
+	}
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, methods); err != nil {
+		return nil, err
+	}
+	return format.Source(buf.Bytes())
+}
+
+func (m *method) Signature() string {
+	params := make([]string, 0, len(m.Params))
+	for _, p := range m.Params {
+		params = append(params, p.Name+" "+p.Type)
+	}
+	return strings.Join(params, ", ")
+}
+
+func (m *method) Args() string {
+	args := make([]string, 0, len(m.Params))
+	for _, p := range m.Params {
+		args = append(args, p.Name)
+	}
+	return strings.Join(args, ", ")
+}
+
+// CallArgs are the values passed as JSON-RPC params.
+func (m *method) CallArgs() string {
+	args := make([]string, 0, len(m.Params)+1)
+	args = append(args, fmt.Sprintf("%q", m.Name))
+	for _, p := range m.Params {
+		if p.Arg != "" {
+			args = append(args, p.Arg)
+		} else {
+			args = append(args, p.Name)
+		}
+	}
+	return strings.Join(args, ", ")
+}
+
+func (m *method) Zero() string {
+	switch {
+	case m.Result == "string":
+		return `""`
+	case m.Result == "bool":
+		return "false"
+	case strings.HasPrefix(m.Result, "*"), strings.HasPrefix(m.Result, "[]"), strings.HasPrefix(m.Result, "map["):
+		return "nil"
+	default:
+		return "0"
+	}
+}
+
+func (m *method) Comment() string {
+	if m.Doc == "" {
+		return ""
+	}
+	var buf strings.Builder
+	for _, line := range strings.Split(strings.TrimSpace(m.Doc), "\n") {
+		buf.WriteString(strings.TrimRight("// "+line, " ") + "\n")
+	}
+	return buf.String()
+}
+
+var tmpl = template.Must(template.New("methods").Parse(header + `
+// Code generated by genmethods from internal/genmethods/methods.go. DO NOT EDIT.
+
+package provider
+
+import (
+	"github.com/ybbus/jsonrpc"
+	"math/big"
+)
+
+var methods = map[string]methodInfo{
+{{- range .}}
+	"{{.Name}}": {idempotent: {{not .Write}}, immutable: {{.Immutable}}, class: {{.Class}}},
+{{- end}}
+}
+{{range .}}{{if not .Handwritten}}
+{{.Comment}}func (provider *Provider) {{.Func}}({{.Signature}}) (*jsonrpc.RPCResponse, error) {
+	return provider.call({{.CallArgs}})
+}
+{{if .Result}}
+func (provider *Provider) {{.Func}}Typed({{.Signature}}) ({{.Result}}, error) {
+	rsp, err := provider.{{.Func}}({{.Args}})
+	if rsp == nil {
+		return {{.Zero}}, err
+	}
+	return {{.Parser}}(rsp)
+}
+{{end}}{{end}}{{end}}`))
+
+const header = `/*
+ * Copyright (C) 2019 Zilliqa
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+`
@@ -0,0 +1,31 @@
+/*
+ * Copyright (C) 2019 Zilliqa
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+package main
+
+import (
+	"github.com/stretchr/testify/assert"
+	"io/ioutil"
+	"testing"
+)
+
+func TestGenerate_UpToDate(t *testing.T) {
+	source, err := generate()
+	assert.Nil(t, err, err)
+	current, err := ioutil.ReadFile("../../methods_gen.go")
+	assert.Nil(t, err, err)
+	assert.Equal(t, string(current), string(source), "methods_gen.go is stale, run go generate in the provider directory")
+}
@@ -0,0 +1,340 @@
+/*
+ * Copyright (C) 2019 Zilliqa
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+package main
+
+type param struct {
+	Name string
+	Type string
+	// Arg is the expression passed as JSON-RPC param, if it is not the parameter itself.
+	Arg string
+}
+
+type method struct {
+	// Name is the JSON-RPC method name, Func the name of the Go method if it differs.
+	Name string
+	Func string
+	Doc  string
+
+	Params []param
+	// Result is the Go type returned by the typed wrapper and Parser the function producing it.
+	// Without a result type only the raw wrapper is generated.
+	Result string
+	Parser string
+
+	// Write marks methods that change chain state; they are never retried.
+	Write bool
+	// Immutable marks methods whose result never changes once the call succeeds, such as a
+	// block by number; only these are cached.
+	Immutable bool
+	// Class is the MethodClass used for rate limiting, ClassRead by default.
+	Class string
+	// Handwritten methods are only registered; their wrappers are written in provider.go.
+	Handwritten bool
+}
+
+// methods declares the JSON-RPC API of the Zilliqa node. Adding a method here and running
+// go generate in the provider directory is all that is needed to support it.
+var methods = []*method{
+	// Blockchain-related methods
+
+	{
+		Name:   "GetNetworkId",
+		Doc:    "Returns the CHAIN_ID of the specified network. This is represented as a String.",
+		Result: "string", Parser: "ParseString",
+	},
+	{
+		Name:   "GetVersion",
+		Doc:    "Returns the software version of the node.",
+		Result: "*NodeVersion", Parser: "ParseNodeVersion",
+	},
+	{
+		Name:   "GetNodeType",
+		Doc:    "Returns the type of the node: Seed, Lookup or, with its shard, a normal node.",
+		Result: "string", Parser: "ParseString",
+	},
+	{
+		Name:   "GetNumPeers",
+		Doc:    "Returns the number of peers in the network.",
+		Result: "uint64", Parser: "ParseUint",
+	},
+	{
+		Name:   "GetBlockchainInfo",
+		Doc:    "Returns the current network statistics for the specified network.",
+		Result: "*BlockchainInfo", Parser: "ParseBlockchainInfo",
+	},
+	{
+		Name:   "GetShardingStructure",
+		Doc:    "Returns the current sharding structure of the network from the specified network's lookup node.",
+		Result: "*ShardingStructure", Parser: "ParseShardingStructure",
+	},
+	{
+		Name:   "GetCurrentDSComm",
+		Doc:    "Returns the public keys of the current DS committee.",
+		Result: "*DSComm", Parser: "ParseDSComm",
+	},
+	{
+		Name:   "GetDsBlock",
+		Doc:    "Returns the details of a specified Directory Service block.",
+		Params: []param{{Name: "blockNumber", Type: "string"}},
+		Result: "*DsBlock", Parser: "ParseDsBlock",
+		Immutable: true,
+	},
+	{
+		Name:   "GetDSBlockVerbose",
+		Doc:    "Returns the details of a specified Directory Service block, including the signatures of its committee.",
+		Params: []param{{Name: "blockNumber", Type: "string"}},
+		Result: "*DsBlock", Parser: "ParseDsBlock",
+		Immutable: true,
+	},
+	{
+		Name:   "GetLatestDsBlock",
+		Doc:    "Returns the details of the most recent Directory Service block.",
+		Result: "*DsBlock", Parser: "ParseDsBlock",
+	},
+	{
+		Name:   "GetNumDSBlocks",
+		Doc:    "Returns the current number of validated Directory Service blocks in the network.\nThis is represented as a String.",
+		Result: "uint64", Parser: "ParseUint",
+	},
+	{
+		Name:   "GetDSBlockRate",
+		Doc:    "Returns the current Directory Service blockrate per second.",
+		Result: "float64", Parser: "ParseFloat",
+	},
+	{
+		Name:   "DSBlockListing",
+		Doc:    "Returns a paginated list of up to 10 Directory Service (DS) blocks and their block hashes for a specified page.\nThe maxPages variable that specifies the maximum number of pages available is also returned.",
+		Params: []param{{Name: "page", Type: "int"}},
+		Result: "*BlockListing", Parser: "ParseBlockListing",
+	},
+	{
+		Name:   "GetTxBlock",
+		Doc:    "Returns the details of a specified Transaction block.",
+		Params: []param{{Name: "blockNumber", Type: "string"}},
+		Result: "*TxBlock", Parser: "ParseTxBlock",
+		Immutable: true,
+	},
+	{
+		Name:   "GetTxBlockVerbose",
+		Doc:    "Returns the details of a specified Transaction block, including the signatures of its committee.",
+		Params: []param{{Name: "blockNumber", Type: "string"}},
+		Result: "*TxBlock", Parser: "ParseTxBlock",
+		Immutable: true,
+	},
+	{
+		Name:   "GetLatestTxBlock",
+		Doc:    "Returns the details of the most recent Transaction block.",
+		Result: "*TxBlock", Parser: "ParseTxBlock",
+	},
+	{
+		Name:   "GetNumTxBlocks",
+		Doc:    "Returns the current number of Transaction blocks in the network.\nThis is represented as a String.",
+		Result: "uint64", Parser: "ParseUint",
+	},
+	{
+		Name:   "GetTxBlockRate",
+		Doc:    "Returns the current Transaction blockrate per second for the network.",
+		Result: "float64", Parser: "ParseFloat",
+	},
+	{
+		Name:   "TxBlockListing",
+		Doc:    "Returns a paginated list of up to 10 Transaction blocks and their block hashes for a specified page.\nThe maxPages variable that specifies the maximum number of pages available is also returned.",
+		Params: []param{{Name: "page", Type: "int"}},
+		Result: "*BlockListing", Parser: "ParseBlockListing",
+	},
+	{
+		Name:   "GetNumTransactions",
+		Doc:    "Returns the current number of validated Transactions in the network.\nThis is represented as a String.",
+		Result: "uint64", Parser: "ParseUint",
+	},
+	{
+		Name:   "GetTransactionRate",
+		Doc:    "Returns the current Transaction rate per second (TPS) of the network.\nThis is represented as an Number.",
+		Result: "float64", Parser: "ParseFloat",
+	},
+	{
+		Name:   "GetCurrentMiniEpoch",
+		Doc:    "Returns the current TX block number of the network.\nThis is represented as a String.",
+		Result: "uint64", Parser: "ParseUint",
+	},
+	{
+		Name:   "GetCurrentDSEpoch",
+		Doc:    "Returns the current number of DS blocks in the network.\nThis is represented as a String.",
+		Result: "uint64", Parser: "ParseUint",
+	},
+	{
+		Name:   "GetPrevDifficulty",
+		Doc:    "Returns the minimum shard difficulty of the previous block.\nThis is represented as an Number.",
+		Result: "uint64", Parser: "ParseDifficulty",
+	},
+	{
+		Name:   "GetPrevDSDifficulty",
+		Doc:    "Returns the minimum DS difficulty of the previous block.\nThis is represented as an Number.",
+		Result: "uint64", Parser: "ParseDifficulty",
+	},
+	{
+		Name:   "GetTotalCoinSupply",
+		Doc:    "Returns the total supply (ZIL) of coins in the network. This is represented as a String.",
+		Result: "*big.Float", Parser: "ParseBigFloat",
+	},
+	{
+		Name:   "GetMinerInfo",
+		Doc:    "Returns the mining nodes (i.e., the members of the DS committee and shards) at the specified DS block.\nNotes: 1. Nodes owned by Zilliqa Research are omitted. 2. dscommittee has no size field since the DS committee size\nis fixed for a given chain. 3. For the Zilliqa Mainnet, this API is only available from DS block 5500 onwards.",
+		Params: []param{{Name: "dsNumber", Type: "string"}},
+		Result: "*MinerInfo", Parser: "ParseMinerInfo",
+		Immutable: true,
+	},
+
+	// Transaction-related methods
+
+	{
+		Name:   "CreateTransaction",
+		Doc:    "Create a new Transaction object and send it to the network to be process.",
+		Params: []param{{Name: "payload", Type: "TransactionPayload", Arg: "&payload"}},
+		Result: "*CreateTxResult", Parser: "ParseCreateTx",
+		Write: true, Class: "ClassWrite",
+	},
+	{
+		Name:   "GetTransaction",
+		Doc:    "Returns the details of a specified Transaction.\nNote: If the transaction had an data field or code field, it will be displayed",
+		Params: []param{{Name: "transactionHash", Type: "string"}},
+		Result: "*TransactionResult", Parser: "ParseTransaction",
+		Immutable: true,
+	},
+	{
+		Name:   "GetTransactionStatus",
+		Doc:    "Returns the status of a specified Transaction, including whether it is pending, dispatched,\nsoft-confirmed or confirmed. Only available on nodes that track transaction status.",
+		Params: []param{{Name: "transactionHash", Type: "string"}},
+		Result: "*TransactionStatus", Parser: "ParseTransactionStatus",
+	},
+	{
+		Name:   "GetRecentTransactions",
+		Doc:    "Returns the most recent 100 transactions that are validated by the Zilliqa network.",
+		Result: "*RecentTransactions", Parser: "ParseRecentTransactions",
+	},
+	{
+		Name:   "GetTransactionsForTxBlock",
+		Doc:    "Returns the validated transactions included within a specfied final transaction block as an array of length i,\nwhere i is the number of shards plus the DS committee. The transactions are grouped based on the group that processed\nthe transaction. The first element of the array refers to the first shard. The last element of the array at index, i,\nrefers to the transactions processed by the DS Committee.",
+		Params: []param{{Name: "txBlockNumber", Type: "string"}},
+		Result: "[][]string", Parser: "ParseTxHashArray",
+		Immutable: true,
+	},
+	{
+		Name:   "GetTransactionsForTxBlockEx",
+		Doc:    "Returns one page of the transactions of GetTransactionsForTxBlock. Pages are numbered from 0;\nGetAllTransactionsForTxBlock fetches all of them.",
+		Params: []param{{Name: "txBlockNumber", Type: "string"}, {Name: "pageNumber", Type: "string"}},
+		Result: "*TxHashPage", Parser: "ParseTxHashPage",
+		Immutable: true,
+	},
+	{
+		Name:   "GetTxnBodiesForTxBlock",
+		Doc:    "Returns the validated transactions, in verbose form, included within a specified final transaction block.",
+		Params: []param{{Name: "txBlockNumber", Type: "string"}},
+		Result: "[]*TransactionResult", Parser: "ParseTransactions",
+		Class: "ClassHeavy", Immutable: true,
+	},
+	{
+		Name:   "GetTxnBodiesForTxBlockEx",
+		Doc:    "Returns one page of the transactions of GetTxnBodiesForTxBlock. Pages are numbered from 0;\nGetAllTxnBodiesForTxBlock fetches all of them.",
+		Params: []param{{Name: "txBlockNumber", Type: "string"}, {Name: "pageNumber", Type: "string"}},
+		Result: "*TxnBodiesPage", Parser: "ParseTxnBodiesPage",
+		Class: "ClassHeavy", Immutable: true,
+	},
+	{
+		Name:   "GetNumTxnsTxEpoch",
+		Doc:    "Returns the number of validated transactions included in this Transaction epoch.\nThis is represented as String.",
+		Result: "uint64", Parser: "ParseUint",
+	},
+	{
+		Name:   "GetNumTxnsDSEpoch",
+		Doc:    "Returns the number of validated transactions included in this DS epoch.\nThis is represented as String.",
+		Result: "uint64", Parser: "ParseUint",
+	},
+	{
+		Name:   "GetMinimumGasPrice",
+		Doc:    "Returns the minimum gas price for this DS epoch, measured in the smallest price unit Qa (or 10^-12 Zil) in Zilliqa.\nThis is represented as a String.",
+		Result: "*big.Int", Parser: "ParseBigInt",
+	},
+	{
+		Name:   "GetPendingTxn",
+		Doc:    "Returns the pending status of a specified Transaction. Possible results are:\n\n\tconfirmed\tcode\tinfo\n\tfalse\t0\tTxn not pending\n\tfalse\t1\tNonce too high\n\tfalse\t2\tCould not fit in as microblock gas limit reached\n\tfalse\t3\tTransaction valid but consensus not reached",
+		Params: []param{{Name: "tx", Type: "string"}},
+		Result: "*PendingTxnStatus", Parser: "ParsePendingTxn",
+	},
+	{
+		Name:   "GetPendingTxns",
+		Doc:    "Returns the pending status of all unvalidated Transactions.\n\n\tFor each entry, the possible results are:\n\n\tconfirmed\tcode\tinfo\n\tfalse\t0\tTxn not pending\n\tfalse\t1\tNonce too high\n\tfalse\t2\tCould not fit in as microblock gas limit reached\n\tfalse\t3\tTransaction valid but consensus not reached",
+		Result: "*PendingTxns", Parser: "ParsePendingTxns",
+	},
+
+	// Contract-related methods
+
+	{
+		Name:   "GetSmartContractCode",
+		Doc:    "Returns the Scilla code associated with a smart contract address.\nThis is represented as a String.",
+		Params: []param{{Name: "contractAddress", Type: "string"}},
+		Result: "string", Parser: "ParseGetContractCode",
+		Immutable: true,
+	},
+	{
+		Name:   "GetSmartContractInit",
+		Doc:    "Returns the initialization (immutable) parameters of a given smart contract, represented in a JSON format.",
+		Params: []param{{Name: "contractAddress", Type: "string"}},
+		Result: "[]Value", Parser: "ParseGetContractInit",
+		Immutable: true,
+	},
+	{
+		Name:   "GetSmartContractState",
+		Doc:    "Returns the state (mutable) variables of a smart contract address, represented in a JSON format.",
+		Params: []param{{Name: "contractAddress", Type: "string"}},
+		Result: "map[string]interface{}", Parser: "ParseSmartContractState",
+		Class: "ClassHeavy",
+	},
+	{
+		Name:        "GetSmartContractSubState",
+		Handwritten: true,
+	},
+	{
+		Name:   "GetSmartContracts",
+		Doc:    "Returns the list of smart contract addresses created by an User's account and the contracts' latest states.",
+		Params: []param{{Name: "userAddress", Type: "string"}},
+		Result: "[]*SmartContract", Parser: "ParseSmartContracts",
+		Class: "ClassHeavy",
+	},
+	{
+		Name:   "GetContractAddressFromTransactionID",
+		Doc:    "Returns a smart contract address of 20 bytes. This is represented as a String.\nNOTE: This only works for contract deployment transactions.",
+		Params: []param{{Name: "transactionId", Type: "string"}},
+		Result: "string", Parser: "ParseString",
+		Immutable: true,
+	},
+	{
+		Name:   "GetStateProof",
+		Doc:    "Returns the Merkle proof of a state variable of a contract, as of the given Tx block.\nvariableHash is the hex encoded hash of the variable name and its map keys.",
+		Params: []param{{Name: "contractAddress", Type: "string"}, {Name: "variableHash", Type: "string"}, {Name: "txBlockNumber", Type: "string"}},
+		Result: "*StateProof", Parser: "ParseStateProof",
+	},
+
+	// Account-related methods
+
+	{
+		Name:   "GetBalance",
+		Doc:    "Returns the current balance of an account, measured in the smallest accounting unit Qa (or 10^-12 Zil).\nThis is represented as a String\nReturns the current nonce of an account. This is represented as an Number.",
+		Params: []param{{Name: "userAddress", Type: "string"}},
+		Result: "*Balance", Parser: "ParseBalance",
+	},
+}
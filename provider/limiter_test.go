@@ -0,0 +1,103 @@
+/*
+ * Copyright (C) 2019 Zilliqa
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+package provider
+
+import (
+	"context"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestWithLimit_MaxInFlight(t *testing.T) {
+	var inFlight, maxInFlight int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := atomic.AddInt32(&inFlight, 1)
+		for {
+			m := atomic.LoadInt32(&maxInFlight)
+			if n <= m || atomic.CompareAndSwapInt32(&maxInFlight, m, n) {
+				break
+			}
+		}
+		time.Sleep(10 * time.Millisecond)
+		atomic.AddInt32(&inFlight, -1)
+		_, _ = w.Write([]byte(`{"id":0,"jsonrpc":"2.0","result":"333"}`))
+	}))
+	defer server.Close()
+
+	provider := NewProviderWithOptions(server.URL, WithLimit(ClassRead, Limit{MaxInFlight: 2}))
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := provider.GetNetworkIdTyped()
+			assert.Nil(t, err, err)
+		}()
+	}
+	wg.Wait()
+	assert.Equal(t, int32(2), maxInFlight)
+}
+
+func TestWithLimit_Rate(t *testing.T) {
+	var count int32
+	server := flakyServer(0, &count)
+	defer server.Close()
+
+	provider := NewProviderWithOptions(server.URL,
+		WithLimit(ClassHeavy, Limit{Rate: 1, Burst: 2}),
+		WithLimit(ClassRead, Limit{Rate: 1000}))
+
+	_, err := provider.GetNetworkId()
+	assert.Nil(t, err, err)
+	_, err = provider.GetSmartContractState("a")
+	assert.Nil(t, err, err)
+	_, err = provider.GetSmartContractState("a")
+	assert.Nil(t, err, err)
+
+	// the burst of heavy calls is used up, the next one has to wait about a second
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	_, err = provider.WithContext(ctx).GetSmartContractState("a")
+	assert.Equal(t, context.DeadlineExceeded, err)
+	assert.Equal(t, int32(3), count)
+
+	// reads are limited separately
+	_, err = provider.GetNetworkId()
+	assert.Nil(t, err, err)
+}
+
+func TestWithLimit_Batch(t *testing.T) {
+	requestCount := 0
+	server := batchServer(t, &requestCount)
+	defer server.Close()
+
+	// a batch is limited by the limit of every class of call in it, not only by the heaviest
+	provider := NewProviderWithOptions(server.URL, WithLimit(ClassRead, Limit{Rate: 1, Burst: 1}))
+	_, err := provider.NewBatch().GetTransaction("a").GetSmartContractState("b").Send()
+	assert.Nil(t, err, err)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	_, err = provider.WithContext(ctx).NewBatch().GetSmartContractState("b").GetTransaction("a").Send()
+	assert.Equal(t, context.DeadlineExceeded, err)
+	assert.Equal(t, 1, requestCount)
+}
@@ -0,0 +1,221 @@
+/*
+ * Copyright (C) 2019 Zilliqa
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+package provider
+
+import "context"
+
+// ListingDirection is the order in which a BlockListingIterator walks the pages of a listing.
+type ListingDirection int
+
+const (
+	// ListingForward starts at page 1, the most recent blocks, and walks towards the genesis block.
+	ListingForward ListingDirection = iota
+	// ListingBackward starts at the last page, the oldest blocks, and walks towards the most recent one.
+	ListingBackward
+)
+
+// BlockListingIterator yields the entries of DSBlockListing or TxBlockListing one by one, in
+// descending block number when walking forward and in ascending block number when walking
+// backward. The next page is fetched while the current one is consumed. The walk is not a
+// snapshot: blocks mined during the walk shift entries to later pages. Walking forward, entries
+// seen before are skipped, so none is yielded twice. Walking backward, the page read before is
+// read again when the next one does not follow on from it, so no entry is missed, and the blocks
+// mined until page 1 is read are yielded too.
+//
+//	it := provider.WithContext(ctx).TxBlockListingIterator(provider.ListingBackward)
+//	defer it.Close()
+//	for it.Next() {
+//		entry := it.Entry()
+//		...
+//	}
+//	if err := it.Err(); err != nil {
+//		...
+//	}
+type BlockListingIterator struct {
+	ctx    context.Context
+	cancel context.CancelFunc
+	pages  chan *listingPage
+
+	direction ListingDirection
+	page      *listingPage
+	entries   []*BlockListingEntry
+	entry     *BlockListingEntry
+	last      *BlockListingEntry
+	err       error
+	closed    bool
+}
+
+type listingPage struct {
+	number  int
+	entries []*BlockListingEntry
+	err     error
+}
+
+type listingFunc func(provider *Provider, page int) (*BlockListing, error)
+
+// DSBlockListingIterator walks DSBlockListing. Cancelling the context of the provider stops it.
+func (provider *Provider) DSBlockListingIterator(direction ListingDirection) *BlockListingIterator {
+	return newBlockListingIterator(provider, (*Provider).DSBlockListingTyped, direction)
+}
+
+// TxBlockListingIterator walks TxBlockListing. Cancelling the context of the provider stops it.
+func (provider *Provider) TxBlockListingIterator(direction ListingDirection) *BlockListingIterator {
+	return newBlockListingIterator(provider, (*Provider).TxBlockListingTyped, direction)
+}
+
+func newBlockListingIterator(provider *Provider, list listingFunc, direction ListingDirection) *BlockListingIterator {
+	ctx, cancel := context.WithCancel(provider.Context())
+	it := &BlockListingIterator{
+		ctx:       ctx,
+		cancel:    cancel,
+		pages:     make(chan *listingPage),
+		direction: direction,
+	}
+	go it.fetch(provider.WithContext(ctx), list)
+	return it
+}
+
+// fetch sends the pages to the iterator, fetching each one while the previous one is consumed.
+func (it *BlockListingIterator) fetch(provider *Provider, list listingFunc) {
+	defer close(it.pages)
+
+	number := 1
+	listing, err := list(provider, number)
+	if err == nil && it.direction == ListingBackward && listing.MaxPages > 1 {
+		number = int(listing.MaxPages)
+		listing, err = list(provider, number)
+	}
+	for {
+		page := &listingPage{number: number, err: err}
+		if err == nil {
+			page.entries = listing.Data
+			if it.direction == ListingBackward {
+				page.entries = make([]*BlockListingEntry, 0, len(listing.Data))
+				for i := len(listing.Data) - 1; i >= 0; i-- {
+					page.entries = append(page.entries, listing.Data[i])
+				}
+			}
+		}
+		select {
+		case it.pages <- page:
+		case <-it.ctx.Done():
+			return
+		}
+		if err != nil {
+			return
+		}
+
+		if it.direction == ListingBackward {
+			number--
+		} else {
+			number++
+		}
+		if number < 1 || number > int(listing.MaxPages) {
+			return
+		}
+		listing, err = list(provider, number)
+		// blocks mined since the previous page was read moved the entries following it onto it
+		for err == nil && it.direction == ListingBackward && skipsAfter(listing, page.entries) &&
+			number < int(listing.MaxPages) {
+			number++
+			listing, err = list(provider, number)
+		}
+	}
+}
+
+// skipsAfter reports whether the oldest entry of listing is not the one right after the newest
+// of entries, the previous page of a backward walk.
+func skipsAfter(listing *BlockListing, entries []*BlockListingEntry) bool {
+	if len(listing.Data) == 0 || len(entries) == 0 {
+		return false
+	}
+	return listing.Data[len(listing.Data)-1].BlockNum > entries[len(entries)-1].BlockNum+1
+}
+
+// Next advances to the next entry. It returns false when the listing is exhausted, when a page
+// could not be fetched or when the context is done; Err tells these cases apart.
+func (it *BlockListingIterator) Next() bool {
+	if it.closed {
+		return false
+	}
+	for {
+		for len(it.entries) > 0 {
+			entry := it.entries[0]
+			it.entries = it.entries[1:]
+			if it.seen(entry) {
+				continue
+			}
+			it.entry = entry
+			it.last = entry
+			return true
+		}
+		if it.err != nil {
+			return false
+		}
+
+		select {
+		case page, ok := <-it.pages:
+			if !ok {
+				return false
+			}
+			if page.err != nil {
+				it.err = page.err
+				return false
+			}
+			it.page = page
+			it.entries = page.entries
+		case <-it.ctx.Done():
+			it.err = it.ctx.Err()
+			return false
+		}
+	}
+}
+
+// seen reports whether entry was already yielded on an earlier page.
+func (it *BlockListingIterator) seen(entry *BlockListingEntry) bool {
+	if it.last == nil {
+		return false
+	}
+	if it.direction == ListingBackward {
+		return entry.BlockNum <= it.last.BlockNum
+	}
+	return entry.BlockNum >= it.last.BlockNum
+}
+
+// Entry returns the current entry.
+func (it *BlockListingIterator) Entry() *BlockListingEntry {
+	return it.entry
+}
+
+// Page returns the number of the page the current entry is on, e.g. to resume a walk later.
+func (it *BlockListingIterator) Page() int {
+	if it.page == nil {
+		return 0
+	}
+	return it.page.number
+}
+
+// Err returns the error that stopped the iteration, if any.
+func (it *BlockListingIterator) Err() error {
+	return it.err
+}
+
+// Close stops the iterator and the fetching of pages. It is safe to call Close more than once.
+func (it *BlockListingIterator) Close() {
+	it.closed = true
+	it.cancel()
+}
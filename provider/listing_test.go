@@ -0,0 +1,169 @@
+/*
+ * Copyright (C) 2019 Zilliqa
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+)
+
+// listingServer serves TxBlockListing for blocks 0 to *height - 1, ten per page and most recent
+// first. Requests for a page in blocked hang until the request is cancelled.
+func listingServer(t *testing.T, height *int64, blocked int) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var request struct {
+			Method string `json:"method"`
+			Params []int  `json:"params"`
+		}
+		err := json.NewDecoder(r.Body).Decode(&request)
+		assert.Nil(t, err, err)
+		assert.Equal(t, "TxBlockListing", request.Method)
+
+		page := request.Params[0]
+		if page == blocked {
+			<-r.Context().Done()
+			return
+		}
+		top := atomic.LoadInt64(height)
+		data := []map[string]interface{}{}
+		for num := top - 1 - int64(page-1)*10; num >= 0 && num >= top-int64(page)*10; num-- {
+			data = append(data, map[string]interface{}{"BlockNum": num, "Hash": fmt.Sprintf("%064x", num)})
+		}
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{
+			"id":      0,
+			"jsonrpc": "2.0",
+			"result":  map[string]interface{}{"data": data, "maxPages": (top + 9) / 10},
+		})
+	}))
+}
+
+func collect(it *BlockListingIterator) []uint64 {
+	var nums []uint64
+	for it.Next() {
+		nums = append(nums, it.Entry().BlockNum)
+	}
+	return nums
+}
+
+func TestBlockListingIterator_Forward(t *testing.T) {
+	height := int64(25)
+	server := listingServer(t, &height, 0)
+	defer server.Close()
+
+	it := NewProvider(server.URL).TxBlockListingIterator(ListingForward)
+	defer it.Close()
+	nums := collect(it)
+	assert.Nil(t, it.Err(), it.Err())
+	assert.Equal(t, 25, len(nums))
+	assert.Equal(t, uint64(24), nums[0])
+	assert.Equal(t, uint64(0), nums[24])
+	assert.Equal(t, 3, it.Page())
+}
+
+func TestBlockListingIterator_Backward(t *testing.T) {
+	height := int64(25)
+	server := listingServer(t, &height, 0)
+	defer server.Close()
+
+	it := NewProvider(server.URL).TxBlockListingIterator(ListingBackward)
+	defer it.Close()
+	for i := uint64(0); i < 25; i++ {
+		assert.True(t, it.Next())
+		assert.Equal(t, i, it.Entry().BlockNum)
+		assert.Equal(t, fmt.Sprintf("%064x", i), it.Entry().Hash)
+	}
+	assert.False(t, it.Next())
+	assert.Nil(t, it.Err(), it.Err())
+}
+
+func TestBlockListingIterator_NewBlocks(t *testing.T) {
+	height := int64(25)
+	server := listingServer(t, &height, 0)
+	defer server.Close()
+
+	it := NewProvider(server.URL).TxBlockListingIterator(ListingForward)
+	defer it.Close()
+	assert.True(t, it.Next())
+	// blocks mined during the walk push entries already yielded onto the next pages
+	atomic.StoreInt64(&height, 32)
+	nums := append([]uint64{it.Entry().BlockNum}, collect(it)...)
+	assert.Nil(t, it.Err(), it.Err())
+	assert.Equal(t, 25, len(nums))
+	for i, num := range nums {
+		assert.Equal(t, uint64(24-i), num)
+	}
+}
+
+func TestBlockListingIterator_NewBlocksBackward(t *testing.T) {
+	height := int64(25)
+	server := listingServer(t, &height, 0)
+	defer server.Close()
+	// three blocks are mined once the last page, blocks 0 to 4, is read; blocks 5 to 7 then
+	// move onto it from page 2
+	var reads int
+	list := func(provider *Provider, page int) (*BlockListing, error) {
+		listing, err := provider.TxBlockListingTyped(page)
+		if reads++; page == 3 && reads == 2 {
+			atomic.StoreInt64(&height, 28)
+		}
+		return listing, err
+	}
+
+	it := newBlockListingIterator(NewProvider(server.URL), list, ListingBackward)
+	defer it.Close()
+	nums := collect(it)
+	assert.Nil(t, it.Err(), it.Err())
+	assert.Equal(t, 28, len(nums))
+	for i, num := range nums {
+		assert.Equal(t, uint64(i), num)
+	}
+}
+
+func TestBlockListingIterator_Cancel(t *testing.T) {
+	height := int64(25)
+	server := listingServer(t, &height, 2)
+	defer server.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	it := NewProvider(server.URL).WithContext(ctx).TxBlockListingIterator(ListingForward)
+	defer it.Close()
+	for i := 0; i < 10; i++ {
+		assert.True(t, it.Next())
+	}
+	cancel()
+	assert.False(t, it.Next())
+	assert.Equal(t, context.Canceled, it.Err())
+}
+
+func TestBlockListingIterator_Close(t *testing.T) {
+	height := int64(25)
+	server := listingServer(t, &height, 2)
+	defer server.Close()
+
+	it := NewProvider(server.URL).TxBlockListingIterator(ListingForward)
+	assert.True(t, it.Next())
+	it.Close()
+	it.Close()
+	assert.False(t, it.Next())
+	assert.Nil(t, it.Err(), it.Err())
+}
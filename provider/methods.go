@@ -0,0 +1,54 @@
+/*
+ * Copyright (C) 2019 Zilliqa
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+package provider
+
+// MethodClass groups RPC methods by their cost for the node, so that rate limits can be set per group.
+type MethodClass int
+
+const (
+	// ClassRead is any call that reads chain state.
+	ClassRead MethodClass = iota
+	// ClassWrite is any call that changes chain state, i.e. CreateTransaction.
+	ClassWrite
+	// ClassHeavy is a read whose result can be large, such as all transaction bodies of a block
+	// or the full state of a contract.
+	ClassHeavy
+)
+
+// methodInfo describes how the provider may treat calls to an RPC method.
+type methodInfo struct {
+	// idempotent methods only read chain state, so a failed call can be sent again,
+	// to the same or to another host, without side effects.
+	idempotent bool
+	// immutable methods return the same result for the same params once they succeed,
+	// so their responses can be cached.
+	immutable bool
+	class     MethodClass
+}
+
+// The RPC methods of the provider are declared in internal/genmethods/methods.go. Each one has
+// a raw wrapper returning the *jsonrpc.RPCResponse and, mostly, a typed wrapper with the suffix
+// Typed, which decodes the result into the structs and Go types declared in block.go and result.go.
+// Typed wrappers return an error both for transport failures and for error responses; error
+// responses go through the parser, so that e.g. errors.Is(err, EmptyBlock) holds.
+//
+// Methods missing from the registry are treated as reads that are not idempotent.
+//go:generate go run ./internal/genmethods
+
+func lookupMethod(method string) methodInfo {
+	return methods[method]
+}
@@ -0,0 +1,728 @@
+/*
+ * Copyright (C) 2019 Zilliqa
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+// Code generated by genmethods from internal/genmethods/methods.go. DO NOT EDIT.
+
+package provider
+
+import (
+	"github.com/ybbus/jsonrpc"
+	"math/big"
+)
+
+var methods = map[string]methodInfo{
+	"GetNetworkId":                        {idempotent: true, immutable: false, class: ClassRead},
+	"GetVersion":                          {idempotent: true, immutable: false, class: ClassRead},
+	"GetNodeType":                         {idempotent: true, immutable: false, class: ClassRead},
+	"GetNumPeers":                         {idempotent: true, immutable: false, class: ClassRead},
+	"GetBlockchainInfo":                   {idempotent: true, immutable: false, class: ClassRead},
+	"GetShardingStructure":                {idempotent: true, immutable: false, class: ClassRead},
+	"GetCurrentDSComm":                    {idempotent: true, immutable: false, class: ClassRead},
+	"GetDsBlock":                          {idempotent: true, immutable: true, class: ClassRead},
+	"GetDSBlockVerbose":                   {idempotent: true, immutable: true, class: ClassRead},
+	"GetLatestDsBlock":                    {idempotent: true, immutable: false, class: ClassRead},
+	"GetNumDSBlocks":                      {idempotent: true, immutable: false, class: ClassRead},
+	"GetDSBlockRate":                      {idempotent: true, immutable: false, class: ClassRead},
+	"DSBlockListing":                      {idempotent: true, immutable: false, class: ClassRead},
+	"GetTxBlock":                          {idempotent: true, immutable: true, class: ClassRead},
+	"GetTxBlockVerbose":                   {idempotent: true, immutable: true, class: ClassRead},
+	"GetLatestTxBlock":                    {idempotent: true, immutable: false, class: ClassRead},
+	"GetNumTxBlocks":                      {idempotent: true, immutable: false, class: ClassRead},
+	"GetTxBlockRate":                      {idempotent: true, immutable: false, class: ClassRead},
+	"TxBlockListing":                      {idempotent: true, immutable: false, class: ClassRead},
+	"GetNumTransactions":                  {idempotent: true, immutable: false, class: ClassRead},
+	"GetTransactionRate":                  {idempotent: true, immutable: false, class: ClassRead},
+	"GetCurrentMiniEpoch":                 {idempotent: true, immutable: false, class: ClassRead},
+	"GetCurrentDSEpoch":                   {idempotent: true, immutable: false, class: ClassRead},
+	"GetPrevDifficulty":                   {idempotent: true, immutable: false, class: ClassRead},
+	"GetPrevDSDifficulty":                 {idempotent: true, immutable: false, class: ClassRead},
+	"GetTotalCoinSupply":                  {idempotent: true, immutable: false, class: ClassRead},
+	"GetMinerInfo":                        {idempotent: true, immutable: true, class: ClassRead},
+	"CreateTransaction":                   {idempotent: false, immutable: false, class: ClassWrite},
+	"GetTransaction":                      {idempotent: true, immutable: true, class: ClassRead},
+	"GetTransactionStatus":                {idempotent: true, immutable: false, class: ClassRead},
+	"GetRecentTransactions":               {idempotent: true, immutable: false, class: ClassRead},
+	"GetTransactionsForTxBlock":           {idempotent: true, immutable: true, class: ClassRead},
+	"GetTransactionsForTxBlockEx":         {idempotent: true, immutable: true, class: ClassRead},
+	"GetTxnBodiesForTxBlock":              {idempotent: true, immutable: true, class: ClassHeavy},
+	"GetTxnBodiesForTxBlockEx":            {idempotent: true, immutable: true, class: ClassHeavy},
+	"GetNumTxnsTxEpoch":                   {idempotent: true, immutable: false, class: ClassRead},
+	"GetNumTxnsDSEpoch":                   {idempotent: true, immutable: false, class: ClassRead},
+	"GetMinimumGasPrice":                  {idempotent: true, immutable: false, class: ClassRead},
+	"GetPendingTxn":                       {idempotent: true, immutable: false, class: ClassRead},
+	"GetPendingTxns":                      {idempotent: true, immutable: false, class: ClassRead},
+	"GetSmartContractCode":                {idempotent: true, immutable: true, class: ClassRead},
+	"GetSmartContractInit":                {idempotent: true, immutable: true, class: ClassRead},
+	"GetSmartContractState":               {idempotent: true, immutable: false, class: ClassHeavy},
+	"GetSmartContractSubState":            {idempotent: true, immutable: false, class: ClassRead},
+	"GetSmartContracts":                   {idempotent: true, immutable: false, class: ClassHeavy},
+	"GetContractAddressFromTransactionID": {idempotent: true, immutable: true, class: ClassRead},
+	"GetStateProof":                       {idempotent: true, immutable: false, class: ClassRead},
+	"GetBalance":                          {idempotent: true, immutable: false, class: ClassRead},
+}
+
+// Returns the CHAIN_ID of the specified network. This is represented as a String.
+func (provider *Provider) GetNetworkId() (*jsonrpc.RPCResponse, error) {
+	return provider.call("GetNetworkId")
+}
+
+func (provider *Provider) GetNetworkIdTyped() (string, error) {
+	rsp, err := provider.GetNetworkId()
+	if rsp == nil {
+		return "", err
+	}
+	return ParseString(rsp)
+}
+
+// Returns the software version of the node.
+func (provider *Provider) GetVersion() (*jsonrpc.RPCResponse, error) {
+	return provider.call("GetVersion")
+}
+
+func (provider *Provider) GetVersionTyped() (*NodeVersion, error) {
+	rsp, err := provider.GetVersion()
+	if rsp == nil {
+		return nil, err
+	}
+	return ParseNodeVersion(rsp)
+}
+
+// Returns the type of the node: Seed, Lookup or, with its shard, a normal node.
+func (provider *Provider) GetNodeType() (*jsonrpc.RPCResponse, error) {
+	return provider.call("GetNodeType")
+}
+
+func (provider *Provider) GetNodeTypeTyped() (string, error) {
+	rsp, err := provider.GetNodeType()
+	if rsp == nil {
+		return "", err
+	}
+	return ParseString(rsp)
+}
+
+// Returns the number of peers in the network.
+func (provider *Provider) GetNumPeers() (*jsonrpc.RPCResponse, error) {
+	return provider.call("GetNumPeers")
+}
+
+func (provider *Provider) GetNumPeersTyped() (uint64, error) {
+	rsp, err := provider.GetNumPeers()
+	if rsp == nil {
+		return 0, err
+	}
+	return ParseUint(rsp)
+}
+
+// Returns the current network statistics for the specified network.
+func (provider *Provider) GetBlockchainInfo() (*jsonrpc.RPCResponse, error) {
+	return provider.call("GetBlockchainInfo")
+}
+
+func (provider *Provider) GetBlockchainInfoTyped() (*BlockchainInfo, error) {
+	rsp, err := provider.GetBlockchainInfo()
+	if rsp == nil {
+		return nil, err
+	}
+	return ParseBlockchainInfo(rsp)
+}
+
+// Returns the current sharding structure of the network from the specified network's lookup node.
+func (provider *Provider) GetShardingStructure() (*jsonrpc.RPCResponse, error) {
+	return provider.call("GetShardingStructure")
+}
+
+func (provider *Provider) GetShardingStructureTyped() (*ShardingStructure, error) {
+	rsp, err := provider.GetShardingStructure()
+	if rsp == nil {
+		return nil, err
+	}
+	return ParseShardingStructure(rsp)
+}
+
+// Returns the public keys of the current DS committee.
+func (provider *Provider) GetCurrentDSComm() (*jsonrpc.RPCResponse, error) {
+	return provider.call("GetCurrentDSComm")
+}
+
+func (provider *Provider) GetCurrentDSCommTyped() (*DSComm, error) {
+	rsp, err := provider.GetCurrentDSComm()
+	if rsp == nil {
+		return nil, err
+	}
+	return ParseDSComm(rsp)
+}
+
+// Returns the details of a specified Directory Service block.
+func (provider *Provider) GetDsBlock(blockNumber string) (*jsonrpc.RPCResponse, error) {
+	return provider.call("GetDsBlock", blockNumber)
+}
+
+func (provider *Provider) GetDsBlockTyped(blockNumber string) (*DsBlock, error) {
+	rsp, err := provider.GetDsBlock(blockNumber)
+	if rsp == nil {
+		return nil, err
+	}
+	return ParseDsBlock(rsp)
+}
+
+// Returns the details of a specified Directory Service block, including the signatures of its committee.
+func (provider *Provider) GetDSBlockVerbose(blockNumber string) (*jsonrpc.RPCResponse, error) {
+	return provider.call("GetDSBlockVerbose", blockNumber)
+}
+
+func (provider *Provider) GetDSBlockVerboseTyped(blockNumber string) (*DsBlock, error) {
+	rsp, err := provider.GetDSBlockVerbose(blockNumber)
+	if rsp == nil {
+		return nil, err
+	}
+	return ParseDsBlock(rsp)
+}
+
+// Returns the details of the most recent Directory Service block.
+func (provider *Provider) GetLatestDsBlock() (*jsonrpc.RPCResponse, error) {
+	return provider.call("GetLatestDsBlock")
+}
+
+func (provider *Provider) GetLatestDsBlockTyped() (*DsBlock, error) {
+	rsp, err := provider.GetLatestDsBlock()
+	if rsp == nil {
+		return nil, err
+	}
+	return ParseDsBlock(rsp)
+}
+
+// Returns the current number of validated Directory Service blocks in the network.
+// This is represented as a String.
+func (provider *Provider) GetNumDSBlocks() (*jsonrpc.RPCResponse, error) {
+	return provider.call("GetNumDSBlocks")
+}
+
+func (provider *Provider) GetNumDSBlocksTyped() (uint64, error) {
+	rsp, err := provider.GetNumDSBlocks()
+	if rsp == nil {
+		return 0, err
+	}
+	return ParseUint(rsp)
+}
+
+// Returns the current Directory Service blockrate per second.
+func (provider *Provider) GetDSBlockRate() (*jsonrpc.RPCResponse, error) {
+	return provider.call("GetDSBlockRate")
+}
+
+func (provider *Provider) GetDSBlockRateTyped() (float64, error) {
+	rsp, err := provider.GetDSBlockRate()
+	if rsp == nil {
+		return 0, err
+	}
+	return ParseFloat(rsp)
+}
+
+// Returns a paginated list of up to 10 Directory Service (DS) blocks and their block hashes for a specified page.
+// The maxPages variable that specifies the maximum number of pages available is also returned.
+func (provider *Provider) DSBlockListing(page int) (*jsonrpc.RPCResponse, error) {
+	return provider.call("DSBlockListing", page)
+}
+
+func (provider *Provider) DSBlockListingTyped(page int) (*BlockListing, error) {
+	rsp, err := provider.DSBlockListing(page)
+	if rsp == nil {
+		return nil, err
+	}
+	return ParseBlockListing(rsp)
+}
+
+// Returns the details of a specified Transaction block.
+func (provider *Provider) GetTxBlock(blockNumber string) (*jsonrpc.RPCResponse, error) {
+	return provider.call("GetTxBlock", blockNumber)
+}
+
+func (provider *Provider) GetTxBlockTyped(blockNumber string) (*TxBlock, error) {
+	rsp, err := provider.GetTxBlock(blockNumber)
+	if rsp == nil {
+		return nil, err
+	}
+	return ParseTxBlock(rsp)
+}
+
+// Returns the details of a specified Transaction block, including the signatures of its committee.
+func (provider *Provider) GetTxBlockVerbose(blockNumber string) (*jsonrpc.RPCResponse, error) {
+	return provider.call("GetTxBlockVerbose", blockNumber)
+}
+
+func (provider *Provider) GetTxBlockVerboseTyped(blockNumber string) (*TxBlock, error) {
+	rsp, err := provider.GetTxBlockVerbose(blockNumber)
+	if rsp == nil {
+		return nil, err
+	}
+	return ParseTxBlock(rsp)
+}
+
+// Returns the details of the most recent Transaction block.
+func (provider *Provider) GetLatestTxBlock() (*jsonrpc.RPCResponse, error) {
+	return provider.call("GetLatestTxBlock")
+}
+
+func (provider *Provider) GetLatestTxBlockTyped() (*TxBlock, error) {
+	rsp, err := provider.GetLatestTxBlock()
+	if rsp == nil {
+		return nil, err
+	}
+	return ParseTxBlock(rsp)
+}
+
+// Returns the current number of Transaction blocks in the network.
+// This is represented as a String.
+func (provider *Provider) GetNumTxBlocks() (*jsonrpc.RPCResponse, error) {
+	return provider.call("GetNumTxBlocks")
+}
+
+func (provider *Provider) GetNumTxBlocksTyped() (uint64, error) {
+	rsp, err := provider.GetNumTxBlocks()
+	if rsp == nil {
+		return 0, err
+	}
+	return ParseUint(rsp)
+}
+
+// Returns the current Transaction blockrate per second for the network.
+func (provider *Provider) GetTxBlockRate() (*jsonrpc.RPCResponse, error) {
+	return provider.call("GetTxBlockRate")
+}
+
+func (provider *Provider) GetTxBlockRateTyped() (float64, error) {
+	rsp, err := provider.GetTxBlockRate()
+	if rsp == nil {
+		return 0, err
+	}
+	return ParseFloat(rsp)
+}
+
+// Returns a paginated list of up to 10 Transaction blocks and their block hashes for a specified page.
+// The maxPages variable that specifies the maximum number of pages available is also returned.
+func (provider *Provider) TxBlockListing(page int) (*jsonrpc.RPCResponse, error) {
+	return provider.call("TxBlockListing", page)
+}
+
+func (provider *Provider) TxBlockListingTyped(page int) (*BlockListing, error) {
+	rsp, err := provider.TxBlockListing(page)
+	if rsp == nil {
+		return nil, err
+	}
+	return ParseBlockListing(rsp)
+}
+
+// Returns the current number of validated Transactions in the network.
+// This is represented as a String.
+func (provider *Provider) GetNumTransactions() (*jsonrpc.RPCResponse, error) {
+	return provider.call("GetNumTransactions")
+}
+
+func (provider *Provider) GetNumTransactionsTyped() (uint64, error) {
+	rsp, err := provider.GetNumTransactions()
+	if rsp == nil {
+		return 0, err
+	}
+	return ParseUint(rsp)
+}
+
+// Returns the current Transaction rate per second (TPS) of the network.
+// This is represented as an Number.
+func (provider *Provider) GetTransactionRate() (*jsonrpc.RPCResponse, error) {
+	return provider.call("GetTransactionRate")
+}
+
+func (provider *Provider) GetTransactionRateTyped() (float64, error) {
+	rsp, err := provider.GetTransactionRate()
+	if rsp == nil {
+		return 0, err
+	}
+	return ParseFloat(rsp)
+}
+
+// Returns the current TX block number of the network.
+// This is represented as a String.
+func (provider *Provider) GetCurrentMiniEpoch() (*jsonrpc.RPCResponse, error) {
+	return provider.call("GetCurrentMiniEpoch")
+}
+
+func (provider *Provider) GetCurrentMiniEpochTyped() (uint64, error) {
+	rsp, err := provider.GetCurrentMiniEpoch()
+	if rsp == nil {
+		return 0, err
+	}
+	return ParseUint(rsp)
+}
+
+// Returns the current number of DS blocks in the network.
+// This is represented as a String.
+func (provider *Provider) GetCurrentDSEpoch() (*jsonrpc.RPCResponse, error) {
+	return provider.call("GetCurrentDSEpoch")
+}
+
+func (provider *Provider) GetCurrentDSEpochTyped() (uint64, error) {
+	rsp, err := provider.GetCurrentDSEpoch()
+	if rsp == nil {
+		return 0, err
+	}
+	return ParseUint(rsp)
+}
+
+// Returns the minimum shard difficulty of the previous block.
+// This is represented as an Number.
+func (provider *Provider) GetPrevDifficulty() (*jsonrpc.RPCResponse, error) {
+	return provider.call("GetPrevDifficulty")
+}
+
+func (provider *Provider) GetPrevDifficultyTyped() (uint64, error) {
+	rsp, err := provider.GetPrevDifficulty()
+	if rsp == nil {
+		return 0, err
+	}
+	return ParseDifficulty(rsp)
+}
+
+// Returns the minimum DS difficulty of the previous block.
+// This is represented as an Number.
+func (provider *Provider) GetPrevDSDifficulty() (*jsonrpc.RPCResponse, error) {
+	return provider.call("GetPrevDSDifficulty")
+}
+
+func (provider *Provider) GetPrevDSDifficultyTyped() (uint64, error) {
+	rsp, err := provider.GetPrevDSDifficulty()
+	if rsp == nil {
+		return 0, err
+	}
+	return ParseDifficulty(rsp)
+}
+
+// Returns the total supply (ZIL) of coins in the network. This is represented as a String.
+func (provider *Provider) GetTotalCoinSupply() (*jsonrpc.RPCResponse, error) {
+	return provider.call("GetTotalCoinSupply")
+}
+
+func (provider *Provider) GetTotalCoinSupplyTyped() (*big.Float, error) {
+	rsp, err := provider.GetTotalCoinSupply()
+	if rsp == nil {
+		return nil, err
+	}
+	return ParseBigFloat(rsp)
+}
+
+// Returns the mining nodes (i.e., the members of the DS committee and shards) at the specified DS block.
+// Notes: 1. Nodes owned by Zilliqa Research are omitted. 2. dscommittee has no size field since the DS committee size
+// is fixed for a given chain. 3. For the Zilliqa Mainnet, this API is only available from DS block 5500 onwards.
+func (provider *Provider) GetMinerInfo(dsNumber string) (*jsonrpc.RPCResponse, error) {
+	return provider.call("GetMinerInfo", dsNumber)
+}
+
+func (provider *Provider) GetMinerInfoTyped(dsNumber string) (*MinerInfo, error) {
+	rsp, err := provider.GetMinerInfo(dsNumber)
+	if rsp == nil {
+		return nil, err
+	}
+	return ParseMinerInfo(rsp)
+}
+
+// Create a new Transaction object and send it to the network to be process.
+func (provider *Provider) CreateTransaction(payload TransactionPayload) (*jsonrpc.RPCResponse, error) {
+	return provider.call("CreateTransaction", &payload)
+}
+
+func (provider *Provider) CreateTransactionTyped(payload TransactionPayload) (*CreateTxResult, error) {
+	rsp, err := provider.CreateTransaction(payload)
+	if rsp == nil {
+		return nil, err
+	}
+	return ParseCreateTx(rsp)
+}
+
+// Returns the details of a specified Transaction.
+// Note: If the transaction had an data field or code field, it will be displayed
+func (provider *Provider) GetTransaction(transactionHash string) (*jsonrpc.RPCResponse, error) {
+	return provider.call("GetTransaction", transactionHash)
+}
+
+func (provider *Provider) GetTransactionTyped(transactionHash string) (*TransactionResult, error) {
+	rsp, err := provider.GetTransaction(transactionHash)
+	if rsp == nil {
+		return nil, err
+	}
+	return ParseTransaction(rsp)
+}
+
+// Returns the status of a specified Transaction, including whether it is pending, dispatched,
+// soft-confirmed or confirmed. Only available on nodes that track transaction status.
+func (provider *Provider) GetTransactionStatus(transactionHash string) (*jsonrpc.RPCResponse, error) {
+	return provider.call("GetTransactionStatus", transactionHash)
+}
+
+func (provider *Provider) GetTransactionStatusTyped(transactionHash string) (*TransactionStatus, error) {
+	rsp, err := provider.GetTransactionStatus(transactionHash)
+	if rsp == nil {
+		return nil, err
+	}
+	return ParseTransactionStatus(rsp)
+}
+
+// Returns the most recent 100 transactions that are validated by the Zilliqa network.
+func (provider *Provider) GetRecentTransactions() (*jsonrpc.RPCResponse, error) {
+	return provider.call("GetRecentTransactions")
+}
+
+func (provider *Provider) GetRecentTransactionsTyped() (*RecentTransactions, error) {
+	rsp, err := provider.GetRecentTransactions()
+	if rsp == nil {
+		return nil, err
+	}
+	return ParseRecentTransactions(rsp)
+}
+
+// Returns the validated transactions included within a specfied final transaction block as an array of length i,
+// where i is the number of shards plus the DS committee. The transactions are grouped based on the group that processed
+// the transaction. The first element of the array refers to the first shard. The last element of the array at index, i,
+// refers to the transactions processed by the DS Committee.
+func (provider *Provider) GetTransactionsForTxBlock(txBlockNumber string) (*jsonrpc.RPCResponse, error) {
+	return provider.call("GetTransactionsForTxBlock", txBlockNumber)
+}
+
+func (provider *Provider) GetTransactionsForTxBlockTyped(txBlockNumber string) ([][]string, error) {
+	rsp, err := provider.GetTransactionsForTxBlock(txBlockNumber)
+	if rsp == nil {
+		return nil, err
+	}
+	return ParseTxHashArray(rsp)
+}
+
+// Returns one page of the transactions of GetTransactionsForTxBlock. Pages are numbered from 0;
+// GetAllTransactionsForTxBlock fetches all of them.
+func (provider *Provider) GetTransactionsForTxBlockEx(txBlockNumber string, pageNumber string) (*jsonrpc.RPCResponse, error) {
+	return provider.call("GetTransactionsForTxBlockEx", txBlockNumber, pageNumber)
+}
+
+func (provider *Provider) GetTransactionsForTxBlockExTyped(txBlockNumber string, pageNumber string) (*TxHashPage, error) {
+	rsp, err := provider.GetTransactionsForTxBlockEx(txBlockNumber, pageNumber)
+	if rsp == nil {
+		return nil, err
+	}
+	return ParseTxHashPage(rsp)
+}
+
+// Returns the validated transactions, in verbose form, included within a specified final transaction block.
+func (provider *Provider) GetTxnBodiesForTxBlock(txBlockNumber string) (*jsonrpc.RPCResponse, error) {
+	return provider.call("GetTxnBodiesForTxBlock", txBlockNumber)
+}
+
+func (provider *Provider) GetTxnBodiesForTxBlockTyped(txBlockNumber string) ([]*TransactionResult, error) {
+	rsp, err := provider.GetTxnBodiesForTxBlock(txBlockNumber)
+	if rsp == nil {
+		return nil, err
+	}
+	return ParseTransactions(rsp)
+}
+
+// Returns one page of the transactions of GetTxnBodiesForTxBlock. Pages are numbered from 0;
+// GetAllTxnBodiesForTxBlock fetches all of them.
+func (provider *Provider) GetTxnBodiesForTxBlockEx(txBlockNumber string, pageNumber string) (*jsonrpc.RPCResponse, error) {
+	return provider.call("GetTxnBodiesForTxBlockEx", txBlockNumber, pageNumber)
+}
+
+func (provider *Provider) GetTxnBodiesForTxBlockExTyped(txBlockNumber string, pageNumber string) (*TxnBodiesPage, error) {
+	rsp, err := provider.GetTxnBodiesForTxBlockEx(txBlockNumber, pageNumber)
+	if rsp == nil {
+		return nil, err
+	}
+	return ParseTxnBodiesPage(rsp)
+}
+
+// Returns the number of validated transactions included in this Transaction epoch.
+// This is represented as String.
+func (provider *Provider) GetNumTxnsTxEpoch() (*jsonrpc.RPCResponse, error) {
+	return provider.call("GetNumTxnsTxEpoch")
+}
+
+func (provider *Provider) GetNumTxnsTxEpochTyped() (uint64, error) {
+	rsp, err := provider.GetNumTxnsTxEpoch()
+	if rsp == nil {
+		return 0, err
+	}
+	return ParseUint(rsp)
+}
+
+// Returns the number of validated transactions included in this DS epoch.
+// This is represented as String.
+func (provider *Provider) GetNumTxnsDSEpoch() (*jsonrpc.RPCResponse, error) {
+	return provider.call("GetNumTxnsDSEpoch")
+}
+
+func (provider *Provider) GetNumTxnsDSEpochTyped() (uint64, error) {
+	rsp, err := provider.GetNumTxnsDSEpoch()
+	if rsp == nil {
+		return 0, err
+	}
+	return ParseUint(rsp)
+}
+
+// Returns the minimum gas price for this DS epoch, measured in the smallest price unit Qa (or 10^-12 Zil) in Zilliqa.
+// This is represented as a String.
+func (provider *Provider) GetMinimumGasPrice() (*jsonrpc.RPCResponse, error) {
+	return provider.call("GetMinimumGasPrice")
+}
+
+func (provider *Provider) GetMinimumGasPriceTyped() (*big.Int, error) {
+	rsp, err := provider.GetMinimumGasPrice()
+	if rsp == nil {
+		return nil, err
+	}
+	return ParseBigInt(rsp)
+}
+
+// Returns the pending status of a specified Transaction. Possible results are:
+//
+//	confirmed	code	info
+//	false	0	Txn not pending
+//	false	1	Nonce too high
+//	false	2	Could not fit in as microblock gas limit reached
+//	false	3	Transaction valid but consensus not reached
+func (provider *Provider) GetPendingTxn(tx string) (*jsonrpc.RPCResponse, error) {
+	return provider.call("GetPendingTxn", tx)
+}
+
+func (provider *Provider) GetPendingTxnTyped(tx string) (*PendingTxnStatus, error) {
+	rsp, err := provider.GetPendingTxn(tx)
+	if rsp == nil {
+		return nil, err
+	}
+	return ParsePendingTxn(rsp)
+}
+
+// Returns the pending status of all unvalidated Transactions.
+//
+//	For each entry, the possible results are:
+//
+//	confirmed	code	info
+//	false	0	Txn not pending
+//	false	1	Nonce too high
+//	false	2	Could not fit in as microblock gas limit reached
+//	false	3	Transaction valid but consensus not reached
+func (provider *Provider) GetPendingTxns() (*jsonrpc.RPCResponse, error) {
+	return provider.call("GetPendingTxns")
+}
+
+func (provider *Provider) GetPendingTxnsTyped() (*PendingTxns, error) {
+	rsp, err := provider.GetPendingTxns()
+	if rsp == nil {
+		return nil, err
+	}
+	return ParsePendingTxns(rsp)
+}
+
+// Returns the Scilla code associated with a smart contract address.
+// This is represented as a String.
+func (provider *Provider) GetSmartContractCode(contractAddress string) (*jsonrpc.RPCResponse, error) {
+	return provider.call("GetSmartContractCode", contractAddress)
+}
+
+func (provider *Provider) GetSmartContractCodeTyped(contractAddress string) (string, error) {
+	rsp, err := provider.GetSmartContractCode(contractAddress)
+	if rsp == nil {
+		return "", err
+	}
+	return ParseGetContractCode(rsp)
+}
+
+// Returns the initialization (immutable) parameters of a given smart contract, represented in a JSON format.
+func (provider *Provider) GetSmartContractInit(contractAddress string) (*jsonrpc.RPCResponse, error) {
+	return provider.call("GetSmartContractInit", contractAddress)
+}
+
+func (provider *Provider) GetSmartContractInitTyped(contractAddress string) ([]Value, error) {
+	rsp, err := provider.GetSmartContractInit(contractAddress)
+	if rsp == nil {
+		return nil, err
+	}
+	return ParseGetContractInit(rsp)
+}
+
+// Returns the state (mutable) variables of a smart contract address, represented in a JSON format.
+func (provider *Provider) GetSmartContractState(contractAddress string) (*jsonrpc.RPCResponse, error) {
+	return provider.call("GetSmartContractState", contractAddress)
+}
+
+func (provider *Provider) GetSmartContractStateTyped(contractAddress string) (map[string]interface{}, error) {
+	rsp, err := provider.GetSmartContractState(contractAddress)
+	if rsp == nil {
+		return nil, err
+	}
+	return ParseSmartContractState(rsp)
+}
+
+// Returns the list of smart contract addresses created by an User's account and the contracts' latest states.
+func (provider *Provider) GetSmartContracts(userAddress string) (*jsonrpc.RPCResponse, error) {
+	return provider.call("GetSmartContracts", userAddress)
+}
+
+func (provider *Provider) GetSmartContractsTyped(userAddress string) ([]*SmartContract, error) {
+	rsp, err := provider.GetSmartContracts(userAddress)
+	if rsp == nil {
+		return nil, err
+	}
+	return ParseSmartContracts(rsp)
+}
+
+// Returns a smart contract address of 20 bytes. This is represented as a String.
+// NOTE: This only works for contract deployment transactions.
+func (provider *Provider) GetContractAddressFromTransactionID(transactionId string) (*jsonrpc.RPCResponse, error) {
+	return provider.call("GetContractAddressFromTransactionID", transactionId)
+}
+
+func (provider *Provider) GetContractAddressFromTransactionIDTyped(transactionId string) (string, error) {
+	rsp, err := provider.GetContractAddressFromTransactionID(transactionId)
+	if rsp == nil {
+		return "", err
+	}
+	return ParseString(rsp)
+}
+
+// Returns the Merkle proof of a state variable of a contract, as of the given Tx block.
+// variableHash is the hex encoded hash of the variable name and its map keys.
+func (provider *Provider) GetStateProof(contractAddress string, variableHash string, txBlockNumber string) (*jsonrpc.RPCResponse, error) {
+	return provider.call("GetStateProof", contractAddress, variableHash, txBlockNumber)
+}
+
+func (provider *Provider) GetStateProofTyped(contractAddress string, variableHash string, txBlockNumber string) (*StateProof, error) {
+	rsp, err := provider.GetStateProof(contractAddress, variableHash, txBlockNumber)
+	if rsp == nil {
+		return nil, err
+	}
+	return ParseStateProof(rsp)
+}
+
+// Returns the current balance of an account, measured in the smallest accounting unit Qa (or 10^-12 Zil).
+// This is represented as a String
+// Returns the current nonce of an account. This is represented as an Number.
+func (provider *Provider) GetBalance(userAddress string) (*jsonrpc.RPCResponse, error) {
+	return provider.call("GetBalance", userAddress)
+}
+
+func (provider *Provider) GetBalanceTyped(userAddress string) (*Balance, error) {
+	rsp, err := provider.GetBalance(userAddress)
+	if rsp == nil {
+		return nil, err
+	}
+	return ParseBalance(rsp)
+}
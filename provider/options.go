@@ -0,0 +1,200 @@
+/*
+ * Copyright (C) 2019 Zilliqa
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+package provider
+
+import (
+	"encoding/base64"
+	"github.com/Zilliqa/gozilliqa-sdk/logging"
+	"net/http"
+	"time"
+)
+
+// Option configures a Provider created by NewProviderWithOptions.
+type Option func(o *options)
+
+type options struct {
+	httpClient   *http.Client
+	roundTripper http.RoundTripper
+	headers      map[string]string
+	timeout      time.Duration
+	maxBatchSize int
+	retry        *RetryPolicy
+	fallbacks    []string
+	strategy     FailoverStrategy
+	limits       map[MethodClass]Limit
+	cache        Cache
+	interceptors []Interceptor
+	logger       logging.Logger
+}
+
+// WithHTTPClient makes the provider send every request, including GetSmartContractSubState,
+// through client instead of a default http.Client.
+func WithHTTPClient(client *http.Client) Option {
+	return func(o *options) {
+		o.httpClient = client
+	}
+}
+
+// WithRoundTripper replaces the RoundTripper of the HTTP client used by the provider,
+// e.g. to go through a proxy or to present a client certificate.
+func WithRoundTripper(roundTripper http.RoundTripper) Option {
+	return func(o *options) {
+		o.roundTripper = roundTripper
+	}
+}
+
+// WithHeader adds a header, such as an API key, to every request.
+func WithHeader(key, value string) Option {
+	return func(o *options) {
+		o.headers[key] = value
+	}
+}
+
+// WithBasicAuth authenticates every request with HTTP basic auth.
+func WithBasicAuth(username, password string) Option {
+	credentials := base64.StdEncoding.EncodeToString([]byte(username + ":" + password))
+	return WithHeader("Authorization", "Basic "+credentials)
+}
+
+// WithTimeout bounds the duration of each single request. A deadline on the context passed
+// to WithContext still applies if it is shorter.
+func WithTimeout(timeout time.Duration) Option {
+	return func(o *options) {
+		o.timeout = timeout
+	}
+}
+
+// WithMaxBatchSize sets the maximum number of calls that batches created by NewBatch send in one request.
+func WithMaxBatchSize(maxBatchSize int) Option {
+	return func(o *options) {
+		o.maxBatchSize = maxBatchSize
+	}
+}
+
+// WithRetry retries idempotent calls that failed because of the network, a request timeout or
+// an HTTP 429 or 5xx status. Without this option such calls are attempted once per host.
+func WithRetry(policy RetryPolicy) Option {
+	return func(o *options) {
+		o.retry = &policy
+	}
+}
+
+// WithFallbackHosts adds hosts that calls fail over to when the main host, or another fallback,
+// fails. Only idempotent calls fail over; CreateTransaction is sent to a single host.
+func WithFallbackHosts(hosts ...string) Option {
+	return func(o *options) {
+		o.fallbacks = append(o.fallbacks, hosts...)
+	}
+}
+
+// WithFailoverStrategy chooses how calls are spread over the main and the fallback hosts.
+// The default is FailoverOrdered.
+func WithFailoverStrategy(strategy FailoverStrategy) Option {
+	return func(o *options) {
+		o.strategy = strategy
+	}
+}
+
+// WithLimit throttles the requests of a method class. The limit is shared by every goroutine
+// using the provider or a copy of it made by WithContext. Retries count as requests.
+func WithLimit(class MethodClass, limit Limit) Option {
+	return func(o *options) {
+		o.limits[class] = limit
+	}
+}
+
+// WithCache keeps the results of calls that never change, such as GetTxBlock, GetTransaction
+// or GetSmartContractCode, in cache and answers repeated calls from it, also those made through
+// a Batch. CacheStats reports how often the cache was hit.
+func WithCache(cache Cache) Option {
+	return func(o *options) {
+		o.cache = cache
+	}
+}
+
+// WithInterceptors wraps every call, also those sent in a Batch, in interceptors, e.g.
+// LoggingInterceptor and MetricsInterceptor. The first interceptor is the outermost one.
+func WithInterceptors(interceptors ...Interceptor) Option {
+	return func(o *options) {
+		o.interceptors = append(o.interceptors, interceptors...)
+	}
+}
+
+// WithLogger sets the logger returned by Logger, through which Transaction.Confirm and the
+// Walker of the subscription package report their progress. Calls are logged by LoggingInterceptor.
+func WithLogger(logger logging.Logger) Option {
+	return func(o *options) {
+		o.logger = logger
+	}
+}
+
+func NewProviderWithOptions(host string, opts ...Option) *Provider {
+	o := &options{
+		headers:      make(map[string]string),
+		limits:       make(map[MethodClass]Limit),
+		maxBatchSize: DefaultMaxBatchSize,
+	}
+	for _, opt := range opts {
+		opt(o)
+	}
+
+	httpClient := &http.Client{}
+	if o.httpClient != nil {
+		httpClient = o.httpClient
+	}
+	if o.roundTripper != nil {
+		client := *httpClient
+		client.Transport = o.roundTripper
+		httpClient = &client
+	}
+
+	var transports []*transport
+	for _, h := range append([]string{host}, o.fallbacks...) {
+		transports = append(transports, &transport{
+			host:       h,
+			httpClient: httpClient,
+			headers:    o.headers,
+			timeout:    o.timeout,
+		})
+	}
+
+	retry := RetryPolicy{MaxAttempts: len(transports)}
+	if o.retry != nil {
+		retry = *o.retry
+	}
+
+	limiters := make(map[MethodClass]*limiter, len(o.limits))
+	for class, limit := range o.limits {
+		limiters[class] = newLimiter(limit)
+	}
+
+	var cache *responseCache
+	if o.cache != nil {
+		cache = &responseCache{backend: o.cache}
+	}
+
+	return &Provider{
+		host:         host,
+		limiters:     limiters,
+		cache:        cache,
+		interceptors: o.interceptors,
+		logger:       logging.OrNop(o.logger),
+		endpoints:    newEndpointPool(transports, o.strategy),
+		retry:        retry,
+		maxBatchSize: o.maxBatchSize,
+	}
+}
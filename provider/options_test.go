@@ -0,0 +1,79 @@
+/*
+ * Copyright (C) 2019 Zilliqa
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+package provider
+
+import (
+	"context"
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+type countingRoundTripper struct {
+	count int
+}
+
+func (c *countingRoundTripper) RoundTrip(r *http.Request) (*http.Response, error) {
+	c.count++
+	return http.DefaultTransport.RoundTrip(r)
+}
+
+func TestNewProviderWithOptions(t *testing.T) {
+	var headers []http.Header
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		headers = append(headers, r.Header)
+		_, _ = w.Write([]byte(`{"id":0,"jsonrpc":"2.0","result":"333"}`))
+	}))
+	defer server.Close()
+
+	roundTripper := &countingRoundTripper{}
+	provider := NewProviderWithOptions(server.URL,
+		WithRoundTripper(roundTripper),
+		WithHeader("X-Api-Key", "secret"),
+		WithBasicAuth("user", "pass"))
+
+	id, err := provider.GetNetworkIdTyped()
+	assert.Nil(t, err, err)
+	assert.Equal(t, "333", id)
+	_, err = provider.GetSmartContractSubState("9611c53BE6d1b32058b2747bdeCECed7e1216793", "admins", []interface{}{})
+	assert.Nil(t, err, err)
+
+	assert.Equal(t, 2, roundTripper.count)
+	for _, h := range headers {
+		assert.Equal(t, "secret", h.Get("X-Api-Key"))
+		assert.Equal(t, "Basic dXNlcjpwYXNz", h.Get("Authorization"))
+	}
+}
+
+func TestWithTimeout(t *testing.T) {
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-release:
+		case <-r.Context().Done():
+		}
+	}))
+	defer server.Close()
+	defer close(release)
+
+	provider := NewProviderWithOptions(server.URL, WithTimeout(50*time.Millisecond))
+	_, err := provider.GetNetworkId()
+	assert.True(t, errors.Is(err, context.DeadlineExceeded), err)
+}
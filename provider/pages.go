@@ -0,0 +1,53 @@
+/*
+ * Copyright (C) 2019 Zilliqa
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+package provider
+
+import "strconv"
+
+// GetAllTransactionsForTxBlock fetches every page of GetTransactionsForTxBlockEx and returns the
+// hashes grouped like GetTransactionsForTxBlock: one list per shard, the DS committee last.
+func (provider *Provider) GetAllTransactionsForTxBlock(txBlockNumber string) ([][]string, error) {
+	var all [][]string
+	for page, numPages := uint64(0), uint64(1); page < numPages; page++ {
+		result, err := provider.GetTransactionsForTxBlockExTyped(txBlockNumber, strconv.FormatUint(page, 10))
+		if err != nil {
+			return nil, err
+		}
+		numPages = result.NumPages
+		for i, hashes := range result.Transactions {
+			if i >= len(all) {
+				all = append(all, []string{})
+			}
+			all[i] = append(all[i], hashes...)
+		}
+	}
+	return all, nil
+}
+
+// GetAllTxnBodiesForTxBlock fetches every page of GetTxnBodiesForTxBlockEx.
+func (provider *Provider) GetAllTxnBodiesForTxBlock(txBlockNumber string) ([]*TransactionResult, error) {
+	var all []*TransactionResult
+	for page, numPages := uint64(0), uint64(1); page < numPages; page++ {
+		result, err := provider.GetTxnBodiesForTxBlockExTyped(txBlockNumber, strconv.FormatUint(page, 10))
+		if err != nil {
+			return nil, err
+		}
+		numPages = result.NumPages
+		all = append(all, result.Transactions...)
+	}
+	return all, nil
+}
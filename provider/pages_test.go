@@ -0,0 +1,95 @@
+/*
+ * Copyright (C) 2019 Zilliqa
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+package provider
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// pagedServer serves the Ex methods for a block of five transactions, two per page.
+func pagedServer(t *testing.T) *httptest.Server {
+	hashes := []string{"a", "b", "c", "d", "e"}
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var request struct {
+			Method string   `json:"method"`
+			Params []string `json:"params"`
+		}
+		err := json.NewDecoder(r.Body).Decode(&request)
+		assert.Nil(t, err, err)
+
+		var page int
+		_, _ = fmt.Sscan(request.Params[1], &page)
+		end := 2*page + 2
+		if end > len(hashes) {
+			end = len(hashes)
+		}
+		var transactions interface{}
+		switch request.Method {
+		case "GetTransactionsForTxBlockEx":
+			transactions = [][]string{hashes[2*page : end], {}}
+		case "GetTxnBodiesForTxBlockEx":
+			bodies := []map[string]string{}
+			for _, hash := range hashes[2*page : end] {
+				bodies = append(bodies, map[string]string{"ID": hash})
+			}
+			transactions = bodies
+		}
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{
+			"id":      0,
+			"jsonrpc": "2.0",
+			"result":  map[string]interface{}{"CurrPage": page, "NumPages": 3, "Transactions": transactions},
+		})
+	}))
+}
+
+func TestProvider_GetAllTransactionsForTxBlock(t *testing.T) {
+	server := pagedServer(t)
+	defer server.Close()
+	provider := NewProvider(server.URL)
+
+	hashes, err := provider.GetAllTransactionsForTxBlock("1")
+	assert.Nil(t, err, err)
+	assert.Equal(t, [][]string{{"a", "b", "c", "d", "e"}, {}}, hashes)
+
+	bodies, err := provider.GetAllTxnBodiesForTxBlock("1")
+	assert.Nil(t, err, err)
+	assert.Equal(t, 5, len(bodies))
+	assert.Equal(t, "e", bodies[4].ID)
+}
+
+func TestProvider_GetPendingTxns(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var request struct {
+			Method string        `json:"method"`
+			Params []interface{} `json:"params"`
+		}
+		_ = json.NewDecoder(r.Body).Decode(&request)
+		assert.Equal(t, "GetPendingTxns", request.Method)
+		assert.Equal(t, 0, len(request.Params))
+		_, _ = w.Write([]byte(`{"id":0,"jsonrpc":"2.0","result":{"Txns":[{"TxnHash":"ec5ef8110a285563d0104269081aa77820058067091a9b3f3ae70f38b94abda3","code":1}]}}`))
+	}))
+	defer server.Close()
+
+	pending, err := NewProvider(server.URL).GetPendingTxnsTyped()
+	assert.Nil(t, err, err)
+	assert.Equal(t, 1, pending.Txns[0].Code)
+}
@@ -2,147 +2,343 @@ package provider
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"github.com/ybbus/jsonrpc"
 	"math/big"
 	"strconv"
 )
 
+// EmptyBlock is matched, with errors.Is, by the error the parsers of the transactions of a
+// block return for a block without any. The error is an *RPCError, wrapped, so the code and
+// message of the node are kept.
 var EmptyBlock = fmt.Errorf("empty block")
 var NotContract = fmt.Errorf("Address not contract address")
 
-func ParseTxBlock(rpcResult *jsonrpc.RPCResponse) (*TxBlock, error) {
+// parseResult decodes the result of rpcResult into out, which must be a pointer.
+// Errors are prefixed with name so callers can tell which parser failed.
+func parseResult(name string, rpcResult *jsonrpc.RPCResponse, out interface{}) error {
+	if rpcResult == nil {
+		return fmt.Errorf("%s: rpc response is nil", name)
+	}
 	if rpcResult.Error != nil {
-		return nil, fmt.Errorf("ParseTxBlock: resp code %d, msg %s", rpcResult.Error.Code,
-			rpcResult.Error.Message)
+		return fmt.Errorf("%s: %w", name, NewRPCError(rpcResult.Error))
 	}
 	jsonResult, err := json.Marshal(rpcResult.Result)
 	if err != nil {
-		return nil, fmt.Errorf("ParseTxBlock: marshal rpc result, %s", err)
+		return fmt.Errorf("%s: marshal rpc result, %s", name, err)
 	}
-	block := &TxBlock{}
-	if err := json.Unmarshal(jsonResult, block); err != nil {
-		return nil, fmt.Errorf("ParseTxBlock: unmarshal txBlock, %s", err)
+	if err := json.Unmarshal(jsonResult, out); err != nil {
+		return fmt.Errorf("%s: unmarshal result, %s", name, err)
 	}
-	return block, nil
+	return nil
 }
 
-func ParseTxHashArray(rpcResult *jsonrpc.RPCResponse) ([][]string, error) {
-	if rpcResult.Error != nil {
-		if rpcResult.Error.Message == "TxBlock has no transactions" {
-			return nil, EmptyBlock
-		}
-		return nil, fmt.Errorf("ParseTxHashArray: resp code %d, msg %s", rpcResult.Error.Code,
-			rpcResult.Error.Message)
+func parseUint(name string, rpcResult *jsonrpc.RPCResponse) (uint64, error) {
+	var result string
+	if err := parseResult(name, rpcResult, &result); err != nil {
+		return 0, err
 	}
-	jsonResult, err := json.Marshal(rpcResult.Result)
+	n, err := strconv.ParseUint(result, 10, 64)
 	if err != nil {
-		return nil, fmt.Errorf("ParseTxHashArray: marshal rpc result, %s", err)
+		return 0, fmt.Errorf("%s: result %s invalid, %s", name, result, err)
 	}
-	result := [][]string{}
-	if err := json.Unmarshal(jsonResult, &result); err != nil {
-		return nil, fmt.Errorf("ParseTxHashArray: unmarshal result, %s", err)
+	return n, nil
+}
+
+// parseNumber is parseUint for results sent either as a JSON string or as a JSON number.
+func parseNumber(name string, rpcResult *jsonrpc.RPCResponse) (uint64, error) {
+	var result json.Number
+	if err := parseResult(name, rpcResult, &result); err != nil {
+		return 0, err
 	}
-	return result, nil
+	n, err := strconv.ParseUint(result.String(), 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("%s: result %s invalid, %s", name, result, err)
+	}
+	return n, nil
 }
 
-func ParseBlockHeight(rpcResult *jsonrpc.RPCResponse) (uint64, error) {
-	if rpcResult.Error != nil {
-		return 0, fmt.Errorf("ParseBlockHeight: resp code %d, msg %s", rpcResult.Error.Code,
-			rpcResult.Error.Message)
+func parseBigInt(name string, rpcResult *jsonrpc.RPCResponse) (*big.Int, error) {
+	var result string
+	if err := parseResult(name, rpcResult, &result); err != nil {
+		return nil, err
 	}
-	if heightString, ok := rpcResult.Result.(string); ok {
-		height, err := strconv.ParseUint(heightString, 10, 64)
-		if err != nil {
-			return 0, fmt.Errorf("ParseBlockHeight: result %s invalid, %s", heightString, err)
-		}
-		return height, nil
-	} else {
-		return 0, fmt.Errorf("ParseBlockHeight: type unmatch")
+	n, ok := new(big.Int).SetString(result, 10)
+	if !ok {
+		return nil, fmt.Errorf("%s: result %s invalid", name, result)
 	}
+	return n, nil
 }
 
-func ParseCreateTxResult(rpcResult *jsonrpc.RPCResponse) (info string, contract string, hash string, err error) {
-	if rpcResult.Error != nil {
-		return "", "", "", fmt.Errorf("ParseCreateTxResult: resp code %d, msg %s",
-			rpcResult.Error.Code, rpcResult.Error.Message)
+func ParseTxBlock(rpcResult *jsonrpc.RPCResponse) (*TxBlock, error) {
+	block := &TxBlock{}
+	if err := parseResult("ParseTxBlock", rpcResult, block); err != nil {
+		return nil, err
 	}
-	type Result struct {
-		ContractAddress string
-		Info            string
-		TranID          string
+	return block, nil
+}
+
+func ParseTxHashArray(rpcResult *jsonrpc.RPCResponse) ([][]string, error) {
+	result := [][]string{}
+	if err := parseResult("ParseTxHashArray", rpcResult, &result); err != nil {
+		return nil, err
 	}
-	jsonResult, err := json.Marshal(rpcResult.Result)
+	return result, nil
+}
+
+func ParseBlockHeight(rpcResult *jsonrpc.RPCResponse) (uint64, error) {
+	return parseUint("ParseBlockHeight", rpcResult)
+}
+
+func ParseCreateTxResult(rpcResult *jsonrpc.RPCResponse) (info string, contract string, hash string, err error) {
+	result, err := ParseCreateTx(rpcResult)
 	if err != nil {
-		err = fmt.Errorf("ParseCreateTxResult: marshal rpc result, %s", err)
-		return
-	}
-	result := &Result{}
-	if err = json.Unmarshal(jsonResult, &result); err != nil {
-		err = fmt.Errorf("ParseCreateTxResult: unmarshal result, %s", err)
-		return
+		return "", "", "", err
 	}
 	return result.Info, result.ContractAddress, result.TranID, nil
 }
 
+func ParseCreateTx(rpcResult *jsonrpc.RPCResponse) (*CreateTxResult, error) {
+	result := &CreateTxResult{}
+	if err := parseResult("ParseCreateTxResult", rpcResult, result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
 func ParseBalanceResp(rpcResult *jsonrpc.RPCResponse) (*big.Int, uint64, error) {
-	if rpcResult.Error != nil {
-		return nil, 0, fmt.Errorf("ParseBalanceResp: resp code %d, msg %s", rpcResult.Error.Code,
-			rpcResult.Error.Message)
+	balance, err := ParseBalance(rpcResult)
+	if err != nil {
+		return nil, 0, err
 	}
-	type Balance struct {
+	return balance.Balance, balance.Nonce, nil
+}
+
+func ParseBalance(rpcResult *jsonrpc.RPCResponse) (*Balance, error) {
+	type balance struct {
 		Balance string `json:"balance"`
 		Nonce   uint64 `json:"nonce"`
 	}
-	jsonResult, err := json.Marshal(rpcResult.Result)
-	if err != nil {
-		return nil, 0, fmt.Errorf("ParseBalanceResp: marshal rpc result, %s", err)
-	}
-	result := &Balance{}
-	if err = json.Unmarshal(jsonResult, &result); err != nil {
-		return nil, 0, fmt.Errorf("ParseBalanceResp: unmarshal result, %s", err)
+	result := &balance{}
+	if err := parseResult("ParseBalanceResp", rpcResult, result); err != nil {
+		return nil, err
 	}
-	balance, ok := new(big.Int).SetString(result.Balance, 10)
+	b, ok := new(big.Int).SetString(result.Balance, 10)
 	if !ok {
-		return nil, 0, fmt.Errorf("ParseBalanceResp: balance %s invalid", result.Balance)
+		return nil, fmt.Errorf("ParseBalanceResp: balance %s invalid", result.Balance)
 	}
-	return balance, result.Nonce, nil
+	return &Balance{Balance: b, Nonce: result.Nonce}, nil
 }
 
 func ParseGetContractCode(rpcResult *jsonrpc.RPCResponse) (code string, err error) {
-	if rpcResult.Error != nil {
-		return "", fmt.Errorf("ParseGetContractCode: resp code %d, msg %s", rpcResult.Error.Code,
-			rpcResult.Error.Message)
-	}
-	type Contract struct {
+	type contract struct {
 		Code string `json:"code"`
 	}
-	jsonResult, err := json.Marshal(rpcResult.Result)
-	if err != nil {
-		return "", fmt.Errorf("ParseGetContractCode: marshal rpc result, %s", err)
-	}
-	result := &Contract{}
-	if err = json.Unmarshal(jsonResult, &result); err != nil {
-		return "", fmt.Errorf("ParseGetContractCode: unmarshal result, %s", err)
+	result := &contract{}
+	if err = parseResult("ParseGetContractCode", rpcResult, result); err != nil {
+		return "", err
 	}
 	return result.Code, nil
 }
 
 func ParseGetContractInit(rpcResult *jsonrpc.RPCResponse) ([]Value, error) {
-	if rpcResult.Error != nil {
-		if rpcResult.Error.Message == "Address not contract address" {
-			return nil, NotContract
-		}
-		return nil, fmt.Errorf("ParseGetContractInit: resp code %d, msg %s", rpcResult.Error.Code,
-			rpcResult.Error.Message)
-	}
-	jsonResult, err := json.Marshal(rpcResult.Result)
-	if err != nil {
-		return nil, fmt.Errorf("ParseGetContractCode: marshal rpc result, %s", err)
+	if rpcResult != nil && rpcResult.Error != nil && errors.Is(NewRPCError(rpcResult.Error), NotContract) {
+		return nil, NotContract
 	}
 	result := make([]Value, 0)
-	if err = json.Unmarshal(jsonResult, &result); err != nil {
-		return nil, fmt.Errorf("ParseGetContractCode: unmarshal result, %s", err)
+	if err := parseResult("ParseGetContractInit", rpcResult, &result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func ParseString(rpcResult *jsonrpc.RPCResponse) (string, error) {
+	var result string
+	if err := parseResult("ParseString", rpcResult, &result); err != nil {
+		return "", err
+	}
+	return result, nil
+}
+
+// ParseUint parses the count returned by methods such as GetNumPeers, which some send as a JSON
+// number and others as a string.
+func ParseUint(rpcResult *jsonrpc.RPCResponse) (uint64, error) {
+	return parseNumber("ParseUint", rpcResult)
+}
+
+func ParseFloat(rpcResult *jsonrpc.RPCResponse) (float64, error) {
+	var result float64
+	if err := parseResult("ParseFloat", rpcResult, &result); err != nil {
+		return 0, err
+	}
+	return result, nil
+}
+
+func ParseBigInt(rpcResult *jsonrpc.RPCResponse) (*big.Int, error) {
+	return parseBigInt("ParseBigInt", rpcResult)
+}
+
+func ParseBigFloat(rpcResult *jsonrpc.RPCResponse) (*big.Float, error) {
+	var result string
+	if err := parseResult("ParseBigFloat", rpcResult, &result); err != nil {
+		return nil, err
+	}
+	n, ok := new(big.Float).SetString(result)
+	if !ok {
+		return nil, fmt.Errorf("ParseBigFloat: result %s invalid", result)
+	}
+	return n, nil
+}
+
+func ParseBlockchainInfo(rpcResult *jsonrpc.RPCResponse) (*BlockchainInfo, error) {
+	info := &BlockchainInfo{}
+	if err := parseResult("ParseBlockchainInfo", rpcResult, info); err != nil {
+		return nil, err
+	}
+	return info, nil
+}
+
+func ParseShardingStructure(rpcResult *jsonrpc.RPCResponse) (*ShardingStructure, error) {
+	sharding := &ShardingStructure{}
+	if err := parseResult("ParseShardingStructure", rpcResult, sharding); err != nil {
+		return nil, err
+	}
+	return sharding, nil
+}
+
+func ParseDsBlock(rpcResult *jsonrpc.RPCResponse) (*DsBlock, error) {
+	block := &DsBlock{}
+	if err := parseResult("ParseDsBlock", rpcResult, block); err != nil {
+		return nil, err
+	}
+	return block, nil
+}
+
+func ParseBlockListing(rpcResult *jsonrpc.RPCResponse) (*BlockListing, error) {
+	listing := &BlockListing{}
+	if err := parseResult("ParseBlockListing", rpcResult, listing); err != nil {
+		return nil, err
+	}
+	return listing, nil
+}
+
+func ParseMinerInfo(rpcResult *jsonrpc.RPCResponse) (*MinerInfo, error) {
+	info := &MinerInfo{}
+	if err := parseResult("ParseMinerInfo", rpcResult, info); err != nil {
+		return nil, err
+	}
+	return info, nil
+}
+
+func ParseTransaction(rpcResult *jsonrpc.RPCResponse) (*TransactionResult, error) {
+	tx := &TransactionResult{}
+	if err := parseResult("ParseTransaction", rpcResult, tx); err != nil {
+		return nil, err
+	}
+	return tx, nil
+}
+
+func ParseTransactions(rpcResult *jsonrpc.RPCResponse) ([]*TransactionResult, error) {
+	txs := make([]*TransactionResult, 0)
+	if err := parseResult("ParseTransactions", rpcResult, &txs); err != nil {
+		return nil, err
+	}
+	return txs, nil
+}
+
+func ParseRecentTransactions(rpcResult *jsonrpc.RPCResponse) (*RecentTransactions, error) {
+	recent := &RecentTransactions{}
+	if err := parseResult("ParseRecentTransactions", rpcResult, recent); err != nil {
+		return nil, err
+	}
+	return recent, nil
+}
+
+func ParsePendingTxn(rpcResult *jsonrpc.RPCResponse) (*PendingTxnStatus, error) {
+	status := &PendingTxnStatus{}
+	if err := parseResult("ParsePendingTxn", rpcResult, status); err != nil {
+		return nil, err
+	}
+	return status, nil
+}
+
+func ParsePendingTxns(rpcResult *jsonrpc.RPCResponse) (*PendingTxns, error) {
+	pending := &PendingTxns{}
+	if err := parseResult("ParsePendingTxns", rpcResult, pending); err != nil {
+		return nil, err
+	}
+	return pending, nil
+}
+
+func ParseSmartContractState(rpcResult *jsonrpc.RPCResponse) (map[string]interface{}, error) {
+	state := make(map[string]interface{})
+	if err := parseResult("ParseSmartContractState", rpcResult, &state); err != nil {
+		return nil, err
+	}
+	return state, nil
+}
+
+func ParseSmartContracts(rpcResult *jsonrpc.RPCResponse) ([]*SmartContract, error) {
+	contracts := make([]*SmartContract, 0)
+	if err := parseResult("ParseSmartContracts", rpcResult, &contracts); err != nil {
+		return nil, err
+	}
+	return contracts, nil
+}
+
+func ParseDifficulty(rpcResult *jsonrpc.RPCResponse) (uint64, error) {
+	var result uint64
+	if err := parseResult("ParseDifficulty", rpcResult, &result); err != nil {
+		return 0, err
 	}
 	return result, nil
 }
+
+func ParseNodeVersion(rpcResult *jsonrpc.RPCResponse) (*NodeVersion, error) {
+	version := &NodeVersion{}
+	if err := parseResult("ParseNodeVersion", rpcResult, version); err != nil {
+		return nil, err
+	}
+	return version, nil
+}
+
+func ParseDSComm(rpcResult *jsonrpc.RPCResponse) (*DSComm, error) {
+	comm := &DSComm{}
+	if err := parseResult("ParseDSComm", rpcResult, comm); err != nil {
+		return nil, err
+	}
+	return comm, nil
+}
+
+func ParseTransactionStatus(rpcResult *jsonrpc.RPCResponse) (*TransactionStatus, error) {
+	status := &TransactionStatus{}
+	if err := parseResult("ParseTransactionStatus", rpcResult, status); err != nil {
+		return nil, err
+	}
+	return status, nil
+}
+
+func ParseTxHashPage(rpcResult *jsonrpc.RPCResponse) (*TxHashPage, error) {
+	page := &TxHashPage{}
+	if err := parseResult("ParseTxHashPage", rpcResult, page); err != nil {
+		return nil, err
+	}
+	return page, nil
+}
+
+func ParseTxnBodiesPage(rpcResult *jsonrpc.RPCResponse) (*TxnBodiesPage, error) {
+	page := &TxnBodiesPage{}
+	if err := parseResult("ParseTxnBodiesPage", rpcResult, page); err != nil {
+		return nil, err
+	}
+	return page, nil
+}
+
+func ParseStateProof(rpcResult *jsonrpc.RPCResponse) (*StateProof, error) {
+	proof := &StateProof{}
+	if err := parseResult("ParseStateProof", rpcResult, proof); err != nil {
+		return nil, err
+	}
+	return proof, nil
+}
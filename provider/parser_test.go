@@ -0,0 +1,203 @@
+/*
+ * Copyright (C) 2019 Zilliqa
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+package provider
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/ybbus/jsonrpc"
+	"testing"
+)
+
+func newResponse(t *testing.T, body string) *jsonrpc.RPCResponse {
+	var rsp *jsonrpc.RPCResponse
+	decoder := json.NewDecoder(bytes.NewReader([]byte(body)))
+	decoder.UseNumber()
+	err := decoder.Decode(&rsp)
+	assert.Nil(t, err, err)
+	return rsp
+}
+
+// roundTrip parses result with parse and checks that the parsed value encodes back to result.
+func roundTrip(t *testing.T, result string, parse func(*jsonrpc.RPCResponse) (interface{}, error)) interface{} {
+	v, err := parse(newResponse(t, `{"id":1,"jsonrpc":"2.0","result":`+result+`}`))
+	assert.Nil(t, err, err)
+	encoded, err := json.Marshal(v)
+	assert.Nil(t, err, err)
+	assert.JSONEq(t, result, string(encoded))
+	return v
+}
+
+func TestParseBalance(t *testing.T) {
+	rsp := newResponse(t, `{"id":1,"jsonrpc":"2.0","result":{"balance":"18446744073709551616000","nonce":12}}`)
+	balance, err := ParseBalance(rsp)
+	assert.Nil(t, err, err)
+	assert.Equal(t, "18446744073709551616000", balance.Balance.String())
+	assert.Equal(t, uint64(12), balance.Nonce)
+
+	rsp = newResponse(t, `{"id":1,"jsonrpc":"2.0","error":{"code":-5,"message":"Account is not created"}}`)
+	_, err = ParseBalance(rsp)
+	assert.NotNil(t, err)
+}
+
+func TestParseBlockchainInfo(t *testing.T) {
+	rsp := newResponse(t, `{"id":1,"jsonrpc":"2.0","result":{"CurrentDSEpoch":"5898","CurrentMiniEpoch":"589778","DSBlockRate":0.00014142137245459714,"NumDSBlocks":"5899","NumPeers":2400,"NumTransactions":"4350627","NumTxBlocks":"589778","NumTxnsDSEpoch":"0","NumTxnsTxEpoch":"0","ShardingStructure":{"NumPeers":[600,600,600]},"TransactionRate":0,"TxBlockRate":0.014138050978963283}}`)
+	info, err := ParseBlockchainInfo(rsp)
+	assert.Nil(t, err, err)
+	assert.Equal(t, "589778", info.NumTxBlocks)
+	assert.Equal(t, uint32(2400), info.NumPeers)
+	assert.Equal(t, []uint32{600, 600, 600}, info.ShardingStructure.NumPeers)
+}
+
+func TestParseBlockListing(t *testing.T) {
+	rsp := newResponse(t, `{"id":1,"jsonrpc":"2.0","result":{"data":[{"BlockNum":5898,"Hash":"4DEED80AFDCC89D5B691DCB54CCB846AD9D823D448A56ACAC4DBE5E1213244C7"},{"BlockNum":5897,"Hash":"BFD8F8D4C8CF16E9A2C1B0E1D2CB5C7F2B5B8F5E0B3E0D1A2F3B4C5D6E7F8091"}],"maxPages":590}}`)
+	listing, err := ParseBlockListing(rsp)
+	assert.Nil(t, err, err)
+	assert.Equal(t, uint64(590), listing.MaxPages)
+	assert.Equal(t, 2, len(listing.Data))
+	assert.Equal(t, uint64(5898), listing.Data[0].BlockNum)
+}
+
+func TestParseTxHashArray(t *testing.T) {
+	rsp := newResponse(t, `{"id":1,"jsonrpc":"2.0","error":{"code":-1,"message":"TxBlock has no transactions"}}`)
+	for _, parse := range []func(*jsonrpc.RPCResponse) error{
+		func(rsp *jsonrpc.RPCResponse) error { _, err := ParseTxHashArray(rsp); return err },
+		func(rsp *jsonrpc.RPCResponse) error { _, err := ParseTransactions(rsp); return err },
+		func(rsp *jsonrpc.RPCResponse) error { _, err := ParseTxHashPage(rsp); return err },
+		func(rsp *jsonrpc.RPCResponse) error { _, err := ParseTxnBodiesPage(rsp); return err },
+	} {
+		err := parse(rsp)
+		assert.True(t, errors.Is(err, EmptyBlock), err)
+		// the response of the node is kept
+		var rpcErr *RPCError
+		assert.True(t, errors.As(err, &rpcErr), err)
+		assert.Equal(t, -1, rpcErr.Code)
+		assert.Equal(t, "TxBlock has no transactions", rpcErr.Message)
+	}
+
+	rsp = newResponse(t, `{"id":1,"jsonrpc":"2.0","result":[["a","b"],null,["c"]]}`)
+	hashes, err := ParseTxHashArray(rsp)
+	assert.Nil(t, err, err)
+	assert.Equal(t, 3, len(hashes))
+	assert.Equal(t, []string{"c"}, hashes[2])
+}
+
+func TestParseNumbers(t *testing.T) {
+	height, err := ParseBlockHeight(newResponse(t, `{"id":1,"jsonrpc":"2.0","result":"589778"}`))
+	assert.Nil(t, err, err)
+	assert.Equal(t, uint64(589778), height)
+
+	_, err = ParseBlockHeight(newResponse(t, `{"id":1,"jsonrpc":"2.0","result":589778}`))
+	assert.NotNil(t, err)
+
+	rate, err := ParseFloat(newResponse(t, `{"id":1,"jsonrpc":"2.0","result":0.014138050978963283}`))
+	assert.Nil(t, err, err)
+	assert.Equal(t, 0.014138050978963283, rate)
+
+	price, err := ParseBigInt(newResponse(t, `{"id":1,"jsonrpc":"2.0","result":"2000000000"}`))
+	assert.Nil(t, err, err)
+	assert.Equal(t, "2000000000", price.String())
+
+	difficulty, err := ParseDifficulty(newResponse(t, `{"id":1,"jsonrpc":"2.0","result":91}`))
+	assert.Nil(t, err, err)
+	assert.Equal(t, uint64(91), difficulty)
+}
+
+// TestParseUint_Number checks that ParseUint, unlike ParseBlockHeight, accepts a JSON number:
+// some of the methods it parses, such as GetNumPeers, return one instead of a string.
+func TestParseUint_Number(t *testing.T) {
+	n, err := ParseUint(newResponse(t, `{"id":1,"jsonrpc":"2.0","result":589778}`))
+	assert.Nil(t, err, err)
+	assert.Equal(t, uint64(589778), n)
+
+	n, err = ParseUint(newResponse(t, `{"id":1,"jsonrpc":"2.0","result":"589778"}`))
+	assert.Nil(t, err, err)
+	assert.Equal(t, uint64(589778), n)
+
+	_, err = ParseUint(newResponse(t, `{"id":1,"jsonrpc":"2.0","result":"-1"}`))
+	assert.NotNil(t, err)
+}
+
+func TestParseTransaction(t *testing.T) {
+	rsp := newResponse(t, `{"id":1,"jsonrpc":"2.0","result":{"ID":"655107c300e86ee6e819af1cbfce097db1510e8cd971d99f32ce2772dcad42f2","amount":"10000000000","gasLimit":"1","gasPrice":"1000000000","nonce":"1","receipt":{"cumulative_gas":"1","epoch_num":"589763","success":true},"senderPubKey":"0x0246E7178DC8253201101E18FD6F6EB9972451D121FC57AA2A06DD5C111E58DC6A","signature":"0x5E6D6B4D4C6B0D5D0B2C7C7A7B0D5C2E2A2B6E8A6E1F1D0B0C1C4C1C6B5C7E7F5E6D6B4D4C6B0D5D0B2C7C7A7B0D5C2E2A2B6E8A6E1F1D0B0C1C4C1C6B5C7E7F","toAddr":"4baf5fada8e5db92c3d3242618c5b47133ae003c","version":"65537"}}`)
+	tx, err := ParseTransaction(rsp)
+	assert.Nil(t, err, err)
+	assert.Equal(t, "10000000000", tx.Amount)
+	assert.True(t, tx.Receipt.Success)
+	assert.Equal(t, "589763", tx.Receipt.EpochNum)
+}
+
+func TestParseMinerInfo(t *testing.T) {
+	info := roundTrip(t, `{"dscommittee":["0x03F25E4B68A7A0EF3D9A7B0C8A6C9D6F4B1D9E1D8F3E6C6E4B2D1A3C5E7F9B1D3C"],"shards":[{"nodes":["0x02A1B2","0x03C4D5"],"size":2}]}`,
+		func(rsp *jsonrpc.RPCResponse) (interface{}, error) {
+			return ParseMinerInfo(rsp)
+		}).(*MinerInfo)
+	assert.Equal(t, 1, len(info.DsCommittee))
+	assert.Equal(t, uint32(2), info.Shards[0].Size)
+	assert.Equal(t, "0x03C4D5", info.Shards[0].Nodes[1])
+}
+
+func TestParseShardingStructure(t *testing.T) {
+	sharding := roundTrip(t, `{"NumPeers":[600,600,598]}`, func(rsp *jsonrpc.RPCResponse) (interface{}, error) {
+		return ParseShardingStructure(rsp)
+	}).(*ShardingStructure)
+	assert.Equal(t, uint64(1798), sharding.TotalPeers())
+}
+
+func TestParseDsBlock(t *testing.T) {
+	block := roundTrip(t, `{"header":{"BlockNum":"9000","Difficulty":95,"DifficultyDS":156,"GasPrice":"2000000000","LeaderPubKey":"0x0232970F8E3D2FA5DE9A8FA4C8DAE7A9D8A3D4A0A6F2AD51A0C9C95F49B3A7A8F","PoWWinners":["0x0207F9C7C1CB6B4A4E2C93D1DB2D4A8E0C2E15F7F3D1A1CAAF8B1A4A3E2D6B0C1","0x0314C6AF5E0F2C6B7D9E1A4B2C3D4E5F6A7B8C9D0E1F2A3B4C5D6E7F8A9B0C1D2"],"PrevHash":"585373fb2c607b324afbe8f592e43b40d0091bbcef56c158e0879ced69648c8e","Timestamp":"1590641169078644"},"signature":"7EE023C56602A17F2C8ABA2BEF290386D7C2CE1ABD8E3621573802FA67528E43A7E3C4B9E9A5A3C5B0A8A0E4F9C2B3D1E5F7A9C1B3D5E7F9A1C3E5A7C9B1D3E5F"}`,
+		func(rsp *jsonrpc.RPCResponse) (interface{}, error) {
+			return ParseDsBlock(rsp)
+		}).(*DsBlock)
+	assert.Equal(t, "9000", block.Header.BlockNum)
+	assert.Equal(t, uint32(156), block.Header.DifficultyDS)
+	assert.Equal(t, 2, len(block.Header.PoWWinners))
+	assert.Nil(t, block.Header.SWInfo)
+}
+
+func TestParseDsBlockVerbose(t *testing.T) {
+	block := roundTrip(t, `{"B1":[true,false,true],"B2":[true,true],"CS1":"FBA696961142862169D03EED67DD302EAB91333CBC4EEFE7EDB230515DA31DC1B9746EEEE5E7C105685E22C483B1021867B3775D30215CA66D5D81543E9FE8B5","CS2":"8B9F3D2E6E5E6A4B2F2D7C1A0E9F8B7C6D5E4F3A2B1C0D9E8F7A6B5C4D3E2F1A0B9C8D7E6F5A4B3C2D1E0F9A8B7C6D5E4F3A2B1C0D9E8F7A6B5C4D3E2F1A0B9C","header":{"BlockNum":"1","CommitteeHash":"da38b3b21b26b71835bb1545246a0a248f97003de302ae20d70aeaf854403029","Difficulty":3,"DifficultyDS":5,"EpochNum":"99","GasPrice":"2000000000","LeaderPubKey":"0x0232970F8E3D2FA5DE9A8FA4C8DAE7A9D8A3D4A0A6F2AD51A0C9C95F49B3A7A8F","MembersEjected":["0x0314C6AF5E0F2C6B7D9E1A4B2C3D4E5F6A7B8C9D0E1F2A3B4C5D6E7F8A9B0C1D2"],"PoWWinners":["0x0207F9C7C1CB6B4A4E2C93D1DB2D4A8E0C2E15F7F3D1A1CAAF8B1A4A3E2D6B0C1"],"PoWWinnersIP":[{"IP":"34.212.122.38","port":33133}],"PrevHash":"585373fb2c607b324afbe8f592e43b40d0091bbcef56c158e0879ced69648c8e","ReservedField":"0000000000000000000000000000000000000000000000000000000000000000","SWInfo":{"Scilla":[0,1,0,"0",0],"Zilliqa":[8,0,4,"1052",7423]},"ShardingHash":"3216a33bfd4801e1907e72c7d529cef99c38d57cd281d0e9d726639fd9882d25","Timestamp":"1606443830834512","Version":2},"signature":"7EE023C56602A17F2C8ABA2BEF290386D7C2CE1ABD8E3621573802FA67528E43A7E3C4B9E9A5A3C5B0A8A0E4F9C2B3D1E5F7A9C1B3D5E7F9A1C3E5A7C9B1D3E5F"}`,
+		func(rsp *jsonrpc.RPCResponse) (interface{}, error) {
+			return ParseDsBlock(rsp)
+		}).(*DsBlock)
+	assert.Equal(t, []bool{true, false, true}, block.B1)
+	assert.Equal(t, "99", block.Header.EpochNum)
+	assert.Equal(t, uint32(33133), block.Header.PoWWinnersIP[0].Port)
+	assert.Equal(t, SWVersion{Minor: 1}, block.Header.SWInfo.Scilla)
+	assert.Equal(t, SWVersion{Major: 8, Fix: 4, UpgradeDS: 1052, Commit: 7423}, block.Header.SWInfo.Zilliqa)
+	assert.Equal(t, uint32(2), block.Header.Version)
+}
+
+func TestSWVersion_UnmarshalJSON(t *testing.T) {
+	var version SWVersion
+	assert.Nil(t, json.Unmarshal([]byte(`[1,2,3,4,5]`), &version))
+	assert.Equal(t, SWVersion{Major: 1, Minor: 2, Fix: 3, UpgradeDS: 4, Commit: 5}, version)
+	assert.NotNil(t, json.Unmarshal([]byte(`[1,2,3,"4"]`), &version))
+	assert.NotNil(t, json.Unmarshal([]byte(`[1,2,3,"x",5]`), &version))
+	assert.NotNil(t, json.Unmarshal([]byte(`{"Major":1}`), &version))
+}
+
+func TestParseTransactionStatus(t *testing.T) {
+	rsp := newResponse(t, `{"id":1,"jsonrpc":"2.0","result":{"ID":"1bb178b023f816e950d862f6505cd79a32bb97e71fd78441cbc3486940a2e1b7","_id":{"$oid":"5fd053d9aa59b9a9a3ba94d4"},"amount":"0","data":"","epochInserted":"1181","epochUpdated":"1181","gasLimit":"1","gasPrice":"2000000000","lastModified":"1607488473697612","modificationState":2,"nonce":"3","senderAddr":"0x2b5b3b2e7a2b0bd4c2d4d3ebc58fd2f2b8b6a1b9","signature":"0x","status":3,"success":true,"toAddr":"0x9cd11b35f2b0a5aa7d5ab0e4a9c0b2f6d6c1c7a1","version":"65537"}}`)
+	status, err := ParseTransactionStatus(rsp)
+	assert.Nil(t, err, err)
+	assert.Equal(t, 3, status.Status)
+	assert.True(t, status.Success)
+	assert.Equal(t, "1181", status.EpochInserted)
+}
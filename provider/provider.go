@@ -17,169 +17,63 @@
 package provider
 
 import (
-	"bytes"
+	"context"
 	"encoding/json"
 	"errors"
+	"fmt"
+	"github.com/Zilliqa/gozilliqa-sdk/logging"
 	"github.com/ybbus/jsonrpc"
-	"io/ioutil"
-	"net/http"
 )
 
 type Provider struct {
-	host      string
-	rpcClient jsonrpc.RPCClient
+	host         string
+	endpoints    *endpointPool
+	retry        RetryPolicy
+	limiters     map[MethodClass]*limiter
+	cache        *responseCache
+	interceptors []Interceptor
+	logger       logging.Logger
+	ctx          context.Context
+	maxBatchSize int
 }
 
 func NewProvider(host string) *Provider {
-
-	rpcClient := jsonrpc.NewClient(host)
-	return &Provider{host: host, rpcClient: rpcClient}
-}
-
-// Returns the CHAIN_ID of the specified network. This is represented as a String.
-func (provider *Provider) GetNetworkId() (*jsonrpc.RPCResponse, error) {
-	return provider.call("GetNetworkId")
-}
-
-// Returns the current network statistics for the specified network.
-func (provider *Provider) GetBlockchainInfo() (*jsonrpc.RPCResponse, error) {
-	return provider.call("GetBlockchainInfo")
-}
-
-func (provider *Provider) GetShardingStructure() (*jsonrpc.RPCResponse, error) {
-	return provider.call("GetShardingStructure")
-}
-
-// Returns the details of a specified Directory Service block.
-func (provider *Provider) GetDsBlock(block_number string) (*jsonrpc.RPCResponse, error) {
-	return provider.call("GetDsBlock", block_number)
-}
-
-// Returns the details of the most recent Directory Service block.
-func (provider *Provider) GetLatestDsBlock() (*jsonrpc.RPCResponse, error) {
-	return provider.call("GetLatestDsBlock")
-}
-
-// Returns the current number of validated Directory Service blocks in the network.
-// This is represented as a String.
-func (provider *Provider) GetNumDSBlocks() (*jsonrpc.RPCResponse, error) {
-	return provider.call("GetNumDSBlocks")
-}
-
-// Returns the current Directory Service blockrate per second.
-func (provider *Provider) GetDSBlockRate() (*jsonrpc.RPCResponse, error) {
-	return provider.call("GetDSBlockRate")
-}
-
-// Returns a paginated list of up to 10 Directory Service (DS) blocks and their block hashes for a specified page.
-// The maxPages variable that specifies the maximum number of pages available is also returned.
-func (provider *Provider) DSBlockListing(ds_block_listing int) (*jsonrpc.RPCResponse, error) {
-	return provider.call("DSBlockListing", ds_block_listing)
-}
-
-// Returns the details of a specified Transaction block.
-func (provider *Provider) GetTxBlock(tx_block string) (*jsonrpc.RPCResponse, error) {
-	return provider.call("GetTxBlock", tx_block)
-}
-
-// Returns the details of the most recent Transaction block.
-func (provider *Provider) GetLatestTxBlock() (*jsonrpc.RPCResponse, error) {
-	return provider.call("GetLatestTxBlock")
-}
-
-// Returns the current number of Transaction blocks in the network.
-// This is represented as a String.
-func (provider *Provider) GetNumTxBlocks() (*jsonrpc.RPCResponse, error) {
-	return provider.call("GetNumTxBlocks")
-}
-
-// Returns the current Transaction blockrate per second for the network.
-func (provider *Provider) GetTxBlockRate() (*jsonrpc.RPCResponse, error) {
-	return provider.call("GetTxBlockRate")
-}
-
-// Returns a paginated list of up to 10 Transaction blocks and their block hashes for a specified page.
-// The maxPages variable that specifies the maximum number of pages available is also returned.
-func (provider *Provider) TxBlockListing(page int) (*jsonrpc.RPCResponse, error) {
-	return provider.call("TxBlockListing", page)
-}
-
-// Returns the current number of validated Transactions in the network.
-// This is represented as a String.
-func (provider *Provider) GetNumTransactions() (*jsonrpc.RPCResponse, error) {
-	return provider.call("GetNumTransactions")
-}
-
-// Returns the current Transaction rate per second (TPS) of the network.
-// This is represented as an Number.
-func (provider *Provider) GetTransactionRate() (*jsonrpc.RPCResponse, error) {
-	return provider.call("GetTransactionRate")
-}
-
-// Returns the current TX block number of the network.
-// This is represented as a String.
-func (provider *Provider) GetCurrentMiniEpoch() (*jsonrpc.RPCResponse, error) {
-	return provider.call("GetCurrentMiniEpoch")
-}
-
-// Returns the current number of DS blocks in the network.
-// This is represented as a String.
-func (provider *Provider) GetCurrentDSEpoch() (*jsonrpc.RPCResponse, error) {
-	return provider.call("GetCurrentDSEpoch")
-}
-
-// Returns the minimum shard difficulty of the previous block.
-// This is represented as an Number.
-func (provider *Provider) GetPrevDifficulty() (*jsonrpc.RPCResponse, error) {
-	return provider.call("GetPrevDifficulty")
-}
-
-
-// Returns the pending status of a specified Transaction. Possible results are:
-//
-//  confirmed	code	info
-//  false	0	Txn not pending
-//  false	1	Nonce too high
-//  false	2	Could not fit in as microblock gas limit reached
-//  false	3	Transaction valid but consensus not reached
-func (provider *Provider) GetPendingTxn(tx string) (*jsonrpc.RPCResponse, error) {
-	return provider.call("GetPendingTxn", tx)
-}
-
-// Returns the pending status of all unvalidated Transactions.
-//
-//  For each entry, the possible results are:
-//
-//  confirmed	code	info
-//  false	0	Txn not pending
-//  false	1	Nonce too high
-//  false	2	Could not fit in as microblock gas limit reached
-//  false	3	Transaction valid but consensus not reached
-func (provider *Provider) GetPendingTxns() (*jsonrpc.RPCResponse,error) {
-	return provider.call("GetPendingTxn")
+	return NewProviderWithOptions(host)
 }
 
-// Returns the minimum DS difficulty of the previous block.
-// This is represented as an Number.
-func (provider *Provider) GetPrevDSDifficulty() (*jsonrpc.RPCResponse, error) {
-	return provider.call("GetPrevDSDifficulty")
+// WithContext returns a shallow copy of the provider whose requests are all bound to ctx,
+// so that cancelling ctx or reaching its deadline aborts in-flight calls.
+// The copy shares the underlying HTTP client with the original provider. A nil ctx is taken
+// as context.Background.
+func (provider *Provider) WithContext(ctx context.Context) *Provider {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	p := *provider
+	p.ctx = ctx
+	return &p
 }
 
-// Returns the total supply (ZIL) of coins in the network. This is represented as a String.
-func (provider *Provider) GetTotalCoinSupply() (*jsonrpc.RPCResponse, error) {
-	return provider.call("GetTotalCoinSupply")
+// Context returns the context bound by WithContext, or context.Background.
+func (provider *Provider) Context() context.Context {
+	if provider.ctx != nil {
+		return provider.ctx
+	}
+	return context.Background()
 }
 
-// Returns the mining nodes (i.e., the members of the DS committee and shards) at the specified DS block.
-// Notes: 1. Nodes owned by Zilliqa Research are omitted. 2. dscommittee has no size field since the DS committee size
-// is fixed for a given chain. 3. For the Zilliqa Mainnet, this API is only available from DS block 5500 onwards.
-func (provider *Provider) GetMinerInfo(dsNumber string) (*jsonrpc.RPCResponse, error) {
-	return provider.call("GetMinerInfo",dsNumber)
+// Logger returns the logger set by WithLogger, or logging.Nop.
+func (provider *Provider) Logger() logging.Logger {
+	return logging.OrNop(provider.logger)
 }
 
-// Create a new Transaction object and send it to the network to be process.
-func (provider *Provider) CreateTransaction(payload TransactionPayload) (*jsonrpc.RPCResponse, error) {
-	return provider.call("CreateTransaction", &payload)
+// CacheStats returns the cache hits and misses of the provider and its copies. It is zero
+// unless the provider was created with WithCache.
+func (provider *Provider) CacheStats() CacheStats {
+	if provider.cache == nil {
+		return CacheStats{}
+	}
+	return provider.cache.stats()
 }
 
 func (provider *Provider) CreateTransactionRaw(payload []byte) (*jsonrpc.RPCResponse, error) {
@@ -191,63 +85,6 @@ func (provider *Provider) CreateTransactionRaw(payload []byte) (*jsonrpc.RPCResp
 	return provider.call("CreateTransaction", &pl)
 }
 
-// Returns the details of a specified Transaction.
-// Note: If the transaction had an data field or code field, it will be displayed
-func (provider *Provider) GetTransaction(transaction_hash string) (*jsonrpc.RPCResponse, error) {
-	return provider.call("GetTransaction", transaction_hash)
-}
-
-// Returns the most recent 100 transactions that are validated by the Zilliqa network.
-func (provider *Provider) GetRecentTransactions() (*jsonrpc.RPCResponse, error) {
-	return provider.call("GetRecentTransactions")
-}
-
-// Returns the validated transactions included within a specfied final transaction block as an array of length i,
-// where i is the number of shards plus the DS committee. The transactions are grouped based on the group that processed
-// the transaction. The first element of the array refers to the first shard. The last element of the array at index, i,
-// refers to the transactions processed by the DS Committee.
-func (provider *Provider) GetTransactionsForTxBlock(tx_block_number string) (*jsonrpc.RPCResponse, error) {
-	return provider.call("GetTransactionsForTxBlock", tx_block_number)
-}
-
-func (provider *Provider) GetTxnBodiesForTxBlock(tx_block_number string) (*jsonrpc.RPCResponse, error) {
-	return provider.call("GetTxnBodiesForTxBlock", tx_block_number)
-}
-
-// Returns the number of validated transactions included in this Transaction epoch.
-// This is represented as String.
-func (provider *Provider) GetNumTxnsTxEpoch() (*jsonrpc.RPCResponse, error) {
-	return provider.call("GetNumTxnsTxEpoch")
-}
-
-// Returns the number of validated transactions included in this DS epoch.
-// This is represented as String.
-func (provider *Provider) GetNumTxnsDSEpoch() (*jsonrpc.RPCResponse, error) {
-	return provider.call("GetNumTxnsDSEpoch")
-}
-
-// Returns the minimum gas price for this DS epoch, measured in the smallest price unit Qa (or 10^-12 Zil) in Zilliqa.
-// This is represented as a String.
-func (provider *Provider) GetMinimumGasPrice() (*jsonrpc.RPCResponse, error) {
-	return provider.call("GetMinimumGasPrice")
-}
-
-// Returns the Scilla code associated with a smart contract address.
-// This is represented as a String.
-func (provider *Provider) GetSmartContractCode(contract_address string) (*jsonrpc.RPCResponse, error) {
-	return provider.call("GetSmartContractCode", contract_address)
-}
-
-// Returns the initialization (immutable) parameters of a given smart contract, represented in a JSON format.
-func (provider *Provider) GetSmartContractInit(contract_address string) (*jsonrpc.RPCResponse, error) {
-	return provider.call("GetSmartContractInit", contract_address)
-}
-
-// Returns the state (mutable) variables of a smart contract address, represented in a JSON format.
-func (provider *Provider) GetSmartContractState(contract_address string) (*jsonrpc.RPCResponse, error) {
-	return provider.call("GetSmartContractState", contract_address)
-}
-
 // Returns the state (or a part specified) of a smart contract address, represented in a JSON format.
 func (provider *Provider) GetSmartContractSubState(contractAddress string, params ...interface{}) (string, error) {
 	//we should hack here for now
@@ -274,56 +111,80 @@ func (provider *Provider) GetSmartContractSubState(contractAddress string, param
 	}
 
 	b, _ := json.Marshal(r)
-	reader := bytes.NewReader(b)
-	request, err := http.NewRequest("POST", provider.host, reader)
+	var result []byte
+	err := provider.intercept(&Call{Method: r.Method, Params: p}, func(ctx context.Context, call *Call) error {
+		method := lookupMethod(r.Method)
+		return provider.do(ctx, method.idempotent, []MethodClass{method.class}, func(ctx context.Context, t *transport) error {
+			body, status, err := t.post(ctx, b)
+			call.RequestSize, call.ResponseSize = len(b), len(body)
+			if err != nil {
+				return err
+			}
+			if status >= 400 {
+				return &HTTPError{Code: status, err: fmt.Errorf("rpc call %s() on %s status code: %d", r.Method, t.host, status)}
+			}
+			result = body
+			return nil
+		})
+	})
 	if err != nil {
 		return "", err
 	}
-	request.Header.Set("Content-Type", "application/json;charset=UTF-8")
-	client := http.Client{}
-	resp, err := client.Do(request)
-	if err != nil {
-		return "", nil
-	}
-	defer resp.Body.Close()
-
-	result, err := ioutil.ReadAll(resp.Body)
-	if err != nil {
-		return "", nil
-	}
 
 	return string(result), nil
 
 }
 
-// Returns the list of smart contract addresses created by an User's account and the contracts' latest states.
-func (provider *Provider) GetSmartContracts(user_address string) (*jsonrpc.RPCResponse, error) {
-	return provider.call("GetSmartContracts", user_address)
-}
-
-// Returns a smart contract address of 20 bytes. This is represented as a String.
-// NOTE: This only works for contract deployment transactions.
-func (provider *Provider) GetContractAddressFromTransactionID(transaction_id string) (*jsonrpc.RPCResponse, error) {
-	return provider.call("GetContractAddressFromTransactionID", transaction_id)
-}
-
-// Returns the current balance of an account, measured in the smallest accounting unit Qa (or 10^-12 Zil).
-// This is represented as a String
-// Returns the current nonce of an account. This is represented as an Number.
-func (provider *Provider) GetBalance(user_address string) (*jsonrpc.RPCResponse, error) {
-	return provider.call("GetBalance", user_address)
+// GetSmartContractSubStateTyped returns the part of the state of a contract selected by variableName
+// and indices, the keys into a map variable. An empty variableName selects the whole state.
+func (provider *Provider) GetSmartContractSubStateTyped(contractAddress, variableName string, indices ...string) (map[string]interface{}, error) {
+	if indices == nil {
+		indices = []string{}
+	}
+	body, err := provider.GetSmartContractSubState(contractAddress, variableName, indices)
+	if err != nil {
+		return nil, err
+	}
+	var rsp *jsonrpc.RPCResponse
+	if err := decodeResponse([]byte(body), &rsp); err != nil {
+		return nil, fmt.Errorf("ParseSmartContractState: could not decode body to rpc response: %s", err)
+	}
+	return ParseSmartContractState(rsp)
 }
 
 func (provider *Provider) call(method_name string, params ...interface{}) (*jsonrpc.RPCResponse, error) {
-	response, err := provider.rpcClient.Call(method_name, params)
+	call := &Call{Method: method_name, Params: params}
+	err := provider.intercept(call, provider.invoke)
+	return call.Response, err
+}
+
+// invoke sends call, or answers it from the cache if its method is immutable.
+func (provider *Provider) invoke(ctx context.Context, call *Call) error {
+	request := jsonrpc.NewRequest(call.Method, call.Params)
+	method := lookupMethod(call.Method)
+	if method.immutable && provider.cache != nil {
+		if response, ok := provider.cache.get(request); ok {
+			call.Response, call.Cached = response, true
+			return nil
+		}
+	}
+
+	err := provider.do(ctx, method.idempotent, []MethodClass{method.class}, func(ctx context.Context, t *transport) error {
+		var err error
+		call.Response, err = t.call(ctx, request, call)
+		return err
+	})
 
 	if err != nil {
-		return nil, err
+		return err
 	}
 
-	if response == nil {
-		return nil, errors.New("rpc response is nil, please check your network status")
+	if call.Response == nil {
+		return errors.New("rpc response is nil, please check your network status")
 	}
 
-	return response, nil
+	if call.Response.Error == nil && method.immutable && provider.cache != nil {
+		provider.cache.set(request, call.Response)
+	}
+	return nil
 }
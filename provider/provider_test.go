@@ -17,10 +17,16 @@
 package provider
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
 	"os"
 	"testing"
+	"time"
 )
 
 func SkipIfCI(t *testing.T) {
@@ -328,3 +334,30 @@ func TestProvider_CreateTransactionRaw(t *testing.T) {
 	result, _ := json.Marshal(response)
 	fmt.Println(string(result))
 }
+
+func TestProvider_WithContext(t *testing.T) {
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-release:
+		case <-r.Context().Done():
+		}
+	}))
+	defer server.Close()
+	defer close(release)
+
+	provider := NewProvider(server.URL)
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	_, err := provider.WithContext(ctx).GetNetworkId()
+	assert.NotNil(t, err)
+	assert.True(t, errors.Is(err, context.DeadlineExceeded), err)
+	assert.True(t, time.Since(start) < 5*time.Second)
+
+	_, err = provider.WithContext(ctx).GetSmartContractSubState("9611c53BE6d1b32058b2747bdeCECed7e1216793", "admins", []interface{}{})
+	assert.NotNil(t, err)
+	assert.Equal(t, context.Background(), provider.Context())
+	assert.Equal(t, context.Background(), provider.WithContext(nil).Context())
+}
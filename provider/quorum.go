@@ -0,0 +1,264 @@
+/*
+ * Copyright (C) 2019 Zilliqa
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/ybbus/jsonrpc"
+	"math/big"
+	"strings"
+)
+
+// ErrNoQuorum is matched by the QuorumError returned when too few endpoints agree.
+var ErrNoQuorum = errors.New("no quorum")
+
+// QuorumProvider sends every read to several providers and returns an answer only once a quorum
+// of them agree on it, so that a single faulty or compromised node cannot forge balances or
+// transactions. Answers are compared after going through the typed parsers, so that e.g. the
+// order of JSON fields or numbers sent as strings do not count as disagreement. Error responses
+// of the node, such as ErrTxnNotFound, are answers too, and so are the errors of parsers, such as
+// NotContract; transport failures are not.
+//
+//	q, err := provider.NewQuorumProvider(2, provider.NewProvider(a), provider.NewProvider(b), provider.NewProvider(c))
+//	balance, err := q.GetBalanceTyped(address)
+type QuorumProvider struct {
+	providers []*Provider
+	quorum    int
+}
+
+// QuorumAnswer is the answer of one endpoint to a quorum read. Result is the normalized result
+// as JSON, or empty if the endpoint answered with an error or failed.
+type QuorumAnswer struct {
+	Host   string
+	Result string
+	Err    error
+}
+
+// QuorumError lists the answers of every endpoint that answered before the read gave up.
+type QuorumError struct {
+	Method  string
+	Quorum  int
+	Answers []QuorumAnswer
+}
+
+func (e *QuorumError) Error() string {
+	answers := make([]string, 0, len(e.Answers))
+	for _, answer := range e.Answers {
+		if answer.Err != nil {
+			answers = append(answers, fmt.Sprintf("%s: %s", answer.Host, answer.Err))
+		} else {
+			answers = append(answers, fmt.Sprintf("%s: %s", answer.Host, answer.Result))
+		}
+	}
+	return fmt.Sprintf("%s: fewer than %d endpoints agree; %s", e.Method, e.Quorum, strings.Join(answers, "; "))
+}
+
+func (e *QuorumError) Is(target error) bool {
+	return target == ErrNoQuorum
+}
+
+// NewQuorumProvider creates a QuorumProvider requiring quorum of providers to agree. A quorum
+// below 1 requires a majority. It fails if there are no providers or fewer than quorum, since
+// no read could ever succeed.
+func NewQuorumProvider(quorum int, providers ...*Provider) (*QuorumProvider, error) {
+	if len(providers) == 0 {
+		return nil, errors.New("quorum provider: no providers")
+	}
+	if quorum < 1 {
+		quorum = len(providers)/2 + 1
+	}
+	if quorum > len(providers) {
+		return nil, fmt.Errorf("quorum provider: quorum %d above %d providers", quorum, len(providers))
+	}
+	return &QuorumProvider{
+		providers: providers,
+		quorum:    quorum,
+	}, nil
+}
+
+// WithContext returns a copy of the quorum provider whose reads are all bound to ctx.
+func (q *QuorumProvider) WithContext(ctx context.Context) *QuorumProvider {
+	providers := make([]*Provider, 0, len(q.providers))
+	for _, p := range q.providers {
+		providers = append(providers, p.WithContext(ctx))
+	}
+	return &QuorumProvider{
+		providers: providers,
+		quorum:    q.quorum,
+	}
+}
+
+// quorumRead is the answer of one provider together with the response and parsed result.
+type quorumRead struct {
+	QuorumAnswer
+	response *jsonrpc.RPCResponse
+	result   interface{}
+	// key is what answers are compared by; empty for transport failures, the error for answers
+	// the parser rejects.
+	key string
+}
+
+func readOne(p *Provider, method string, parse ResultParser, params []interface{}) *quorumRead {
+	read := &quorumRead{QuorumAnswer: QuorumAnswer{Host: p.host}}
+	read.response, read.Err = p.call(method, params...)
+	if read.response == nil {
+		return read
+	}
+	read.result, read.Err = parse(read.response)
+	var rpcErr *RPCError
+	switch {
+	case read.Err == nil:
+		normalized, err := json.Marshal(read.result)
+		if err != nil {
+			read.Err = err
+			return read
+		}
+		read.Result = string(normalized)
+		read.key = read.Result
+	case errors.As(read.Err, &rpcErr):
+		read.key = fmt.Sprintf("error %d %s", rpcErr.Code, rpcErr.Message)
+	default:
+		// the parser rejected the answer, e.g. with NotContract, which other endpoints may
+		// agree on as well
+		read.key = "error " + read.Err.Error()
+	}
+	return read
+}
+
+// read sends the call to every provider and returns the first answer a quorum agrees on, as soon
+// as it is reached.
+func (q *QuorumProvider) read(method string, parse ResultParser, params ...interface{}) (*quorumRead, error) {
+	reads := make(chan *quorumRead, len(q.providers))
+	for _, p := range q.providers {
+		go func(p *Provider) {
+			reads <- readOne(p, method, parse, params)
+		}(p)
+	}
+
+	votes := make(map[string]int)
+	answers := make([]QuorumAnswer, 0, len(q.providers))
+	for range q.providers {
+		read := <-reads
+		answers = append(answers, read.QuorumAnswer)
+		if read.key == "" {
+			continue
+		}
+		votes[read.key]++
+		if votes[read.key] >= q.quorum {
+			return read, read.Err
+		}
+	}
+	return nil, &QuorumError{Method: method, Quorum: q.quorum, Answers: answers}
+}
+
+// Read sends a call to method to every provider and returns the result of parse once a quorum
+// agrees on it. The typed methods of QuorumProvider are built on Read.
+func (q *QuorumProvider) Read(method string, parse ResultParser, params ...interface{}) (interface{}, error) {
+	read, err := q.read(method, parse, params...)
+	if read == nil {
+		return nil, err
+	}
+	return read.result, err
+}
+
+// readRaw is Read for the raw methods, which return the response of an endpoint of the quorum.
+func (q *QuorumProvider) readRaw(method string, parse ResultParser, params ...interface{}) (*jsonrpc.RPCResponse, error) {
+	read, err := q.read(method, parse, params...)
+	if read == nil {
+		return nil, err
+	}
+	return read.response, err
+}
+
+func (q *QuorumProvider) GetNetworkId() (*jsonrpc.RPCResponse, error) {
+	return q.readRaw("GetNetworkId", func(rsp *jsonrpc.RPCResponse) (interface{}, error) {
+		return ParseString(rsp)
+	})
+}
+
+func (q *QuorumProvider) GetMinimumGasPrice() (*jsonrpc.RPCResponse, error) {
+	return q.readRaw("GetMinimumGasPrice", func(rsp *jsonrpc.RPCResponse) (interface{}, error) {
+		return ParseBigInt(rsp)
+	})
+}
+
+func (q *QuorumProvider) GetBalance(userAddress string) (*jsonrpc.RPCResponse, error) {
+	return q.readRaw("GetBalance", func(rsp *jsonrpc.RPCResponse) (interface{}, error) {
+		return ParseBalance(rsp)
+	}, userAddress)
+}
+
+func (q *QuorumProvider) GetNumTxBlocks() (*jsonrpc.RPCResponse, error) {
+	return q.readRaw("GetNumTxBlocks", func(rsp *jsonrpc.RPCResponse) (interface{}, error) {
+		return ParseUint(rsp)
+	})
+}
+
+func (q *QuorumProvider) GetTxBlock(blockNumber string) (*jsonrpc.RPCResponse, error) {
+	return q.readRaw("GetTxBlock", func(rsp *jsonrpc.RPCResponse) (interface{}, error) {
+		return ParseTxBlock(rsp)
+	}, blockNumber)
+}
+
+func (q *QuorumProvider) GetTransaction(transactionHash string) (*jsonrpc.RPCResponse, error) {
+	return q.readRaw("GetTransaction", func(rsp *jsonrpc.RPCResponse) (interface{}, error) {
+		return ParseTransaction(rsp)
+	}, transactionHash)
+}
+
+func (q *QuorumProvider) GetTransactionsForTxBlock(txBlockNumber string) (*jsonrpc.RPCResponse, error) {
+	return q.readRaw("GetTransactionsForTxBlock", func(rsp *jsonrpc.RPCResponse) (interface{}, error) {
+		return ParseTxHashArray(rsp)
+	}, txBlockNumber)
+}
+
+func (q *QuorumProvider) GetMinimumGasPriceTyped() (*big.Int, error) {
+	result, err := q.Read("GetMinimumGasPrice", func(rsp *jsonrpc.RPCResponse) (interface{}, error) {
+		return ParseBigInt(rsp)
+	})
+	price, _ := result.(*big.Int)
+	return price, err
+}
+
+func (q *QuorumProvider) GetBalanceTyped(userAddress string) (*Balance, error) {
+	result, err := q.Read("GetBalance", func(rsp *jsonrpc.RPCResponse) (interface{}, error) {
+		return ParseBalance(rsp)
+	}, userAddress)
+	balance, _ := result.(*Balance)
+	return balance, err
+}
+
+func (q *QuorumProvider) GetTxBlockTyped(blockNumber string) (*TxBlock, error) {
+	result, err := q.Read("GetTxBlock", func(rsp *jsonrpc.RPCResponse) (interface{}, error) {
+		return ParseTxBlock(rsp)
+	}, blockNumber)
+	block, _ := result.(*TxBlock)
+	return block, err
+}
+
+func (q *QuorumProvider) GetTransactionTyped(transactionHash string) (*TransactionResult, error) {
+	result, err := q.Read("GetTransaction", func(rsp *jsonrpc.RPCResponse) (interface{}, error) {
+		return ParseTransaction(rsp)
+	}, transactionHash)
+	transaction, _ := result.(*TransactionResult)
+	return transaction, err
+}
+
+var _ Reader = (*QuorumProvider)(nil)
@@ -0,0 +1,154 @@
+/*
+ * Copyright (C) 2019 Zilliqa
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+package provider
+
+import (
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/ybbus/jsonrpc"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// staticServer answers every call with body.
+func staticServer(body string) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(body))
+	}))
+}
+
+// staticProviders returns a provider for each body and a func closing their servers.
+func staticProviders(bodies ...string) ([]*Provider, func()) {
+	var providers []*Provider
+	var servers []*httptest.Server
+	for _, body := range bodies {
+		server := staticServer(body)
+		servers = append(servers, server)
+		providers = append(providers, NewProvider(server.URL))
+	}
+	return providers, func() {
+		for _, server := range servers {
+			server.Close()
+		}
+	}
+}
+
+func TestQuorumProvider_Agree(t *testing.T) {
+	providers, closeAll := staticProviders(
+		`{"id":1,"jsonrpc":"2.0","result":{"balance":"100","nonce":3}}`,
+		`{"id":1,"jsonrpc":"2.0","result":{"balance":"999","nonce":3}}`,
+		// the same balance, formatted differently
+		`{"jsonrpc":"2.0","id":1,"result":{ "nonce":3, "balance":"100" }}`,
+	)
+	defer closeAll()
+	q, err := NewQuorumProvider(2, providers...)
+	assert.Nil(t, err, err)
+	balance, err := q.GetBalanceTyped("addr")
+	assert.Nil(t, err, err)
+	assert.Equal(t, "100", balance.Balance.String())
+	assert.Equal(t, uint64(3), balance.Nonce)
+
+	rsp, err := q.GetBalance("addr")
+	assert.Nil(t, err, err)
+	assert.Equal(t, "100", rsp.Result.(map[string]interface{})["balance"])
+}
+
+func TestQuorumProvider_Disagree(t *testing.T) {
+	providers, closeAll := staticProviders(
+		`{"id":1,"jsonrpc":"2.0","result":{"balance":"100","nonce":3}}`,
+		`{"id":1,"jsonrpc":"2.0","result":{"balance":"999","nonce":3}}`,
+	)
+	defer closeAll()
+	// an endpoint that is down does not answer at all
+	server := staticServer("")
+	server.Close()
+	q, err := NewQuorumProvider(2, append(providers, NewProvider(server.URL))...)
+	assert.Nil(t, err, err)
+
+	_, err = q.GetBalanceTyped("addr")
+	assert.True(t, errors.Is(err, ErrNoQuorum), err)
+	var quorumErr *QuorumError
+	assert.True(t, errors.As(err, &quorumErr))
+	assert.Equal(t, "GetBalance", quorumErr.Method)
+	assert.Equal(t, 3, len(quorumErr.Answers))
+	results := map[string]bool{}
+	failed := 0
+	for _, answer := range quorumErr.Answers {
+		if answer.Err != nil {
+			failed++
+			assert.Equal(t, server.URL, answer.Host)
+		}
+		results[answer.Result] = true
+	}
+	assert.Equal(t, 1, failed)
+	assert.True(t, results[`{"Balance":100,"Nonce":3}`], results)
+	assert.True(t, strings.Contains(err.Error(), `{"Balance":999,"Nonce":3}`), err.Error())
+}
+
+func TestQuorumProvider_AgreeOnError(t *testing.T) {
+	notFound := `{"id":1,"jsonrpc":"2.0","error":{"code":-20,"message":"Txn Hash not Present"}}`
+	providers, closeAll := staticProviders(notFound, `{"id":1,"jsonrpc":"2.0","result":{"ID":"abc"}}`, notFound)
+	defer closeAll()
+	q, err := NewQuorumProvider(2, providers...)
+	assert.Nil(t, err, err)
+
+	transaction, err := q.GetTransactionTyped("abc")
+	assert.Nil(t, transaction)
+	assert.True(t, errors.Is(err, ErrTxnNotFound), err)
+}
+
+func TestQuorumProvider_AgreeOnParserError(t *testing.T) {
+	empty := `{"id":1,"jsonrpc":"2.0","error":{"code":-1,"message":"TxBlock has no transactions"}}`
+	providers, closeAll := staticProviders(empty, `{"id":1,"jsonrpc":"2.0","result":[["a"]]}`, empty)
+	defer closeAll()
+	q, err := NewQuorumProvider(2, providers...)
+	assert.Nil(t, err, err)
+
+	_, err = q.Read("GetTransactionsForTxBlock", func(rsp *jsonrpc.RPCResponse) (interface{}, error) {
+		return ParseTxHashArray(rsp)
+	}, "1")
+	assert.True(t, errors.Is(err, EmptyBlock), err)
+
+	// a parser may also return a bare sentinel
+	_, err = q.Read("GetSmartContractInit", func(rsp *jsonrpc.RPCResponse) (interface{}, error) {
+		if rsp.Error != nil {
+			return nil, NotContract
+		}
+		return rsp.Result, nil
+	}, "addr")
+	assert.Equal(t, NotContract, err)
+}
+
+func TestNewQuorumProvider_Majority(t *testing.T) {
+	q, err := NewQuorumProvider(0, NewProvider("a"), NewProvider("b"), NewProvider("c"))
+	assert.Nil(t, err, err)
+	assert.Equal(t, 2, q.quorum)
+	q, err = NewQuorumProvider(-1, NewProvider("a"), NewProvider("b"), NewProvider("c"), NewProvider("d"))
+	assert.Nil(t, err, err)
+	assert.Equal(t, 3, q.quorum)
+}
+
+func TestNewQuorumProvider_Invalid(t *testing.T) {
+	_, err := NewQuorumProvider(1)
+	assert.NotNil(t, err)
+	_, err = NewQuorumProvider(0)
+	assert.NotNil(t, err)
+	_, err = NewQuorumProvider(3, NewProvider("a"), NewProvider("b"))
+	assert.NotNil(t, err)
+}
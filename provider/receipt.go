@@ -0,0 +1,174 @@
+/*
+ * Copyright (C) 2019 Zilliqa
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+package provider
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// ErrTxnFailed is matched by the errors returned by Receipt.Err.
+var ErrTxnFailed = errors.New("transaction failed")
+
+// ReceiptErrorCode is an error raised by the node while executing a transaction, as found in
+// the errors of its receipt.
+type ReceiptErrorCode int
+
+const (
+	CheckerFailed ReceiptErrorCode = iota
+	RunnerFailed
+	BalanceTransferFailed
+	ExecuteCmdFailed
+	ExecuteCmdTimeout
+	NoGasRemainingFound
+	NoAcceptedFound
+	CallContractFailed
+	CreateContractFailed
+	JsonOutputCorrupted
+	ContractNotExist
+	StateCorrupted
+	LogEntryInstallFailed
+	MessageCorrupted
+	ReceiptIsNull
+	MaxEdgesReached
+	ChainCallDiffShard
+	PreparationFailed
+	NoOutput
+	OutputIllegal
+	MapDepthMissing
+	GasNotSufficient
+	InternalError
+	LibraryAsRecipient
+	VersionInconsistent
+	LibraryExtractionFailed
+)
+
+var receiptErrorCodes = []struct {
+	name        string
+	description string
+}{
+	CheckerFailed:           {"CHECKER_FAILED", "the contract failed the Scilla checker"},
+	RunnerFailed:            {"RUNNER_FAILED", "the Scilla interpreter failed to run the contract"},
+	BalanceTransferFailed:   {"BALANCE_TRANSFER_FAILED", "the amount could not be transferred"},
+	ExecuteCmdFailed:        {"EXECUTE_CMD_FAILED", "the Scilla interpreter could not be started"},
+	ExecuteCmdTimeout:       {"EXECUTE_CMD_TIMEOUT", "the Scilla interpreter timed out"},
+	NoGasRemainingFound:     {"NO_GAS_REMAINING_FOUND", "the interpreter output has no remaining gas"},
+	NoAcceptedFound:         {"NO_ACCEPTED_FOUND", "the interpreter output does not tell whether the amount was accepted"},
+	CallContractFailed:      {"CALL_CONTRACT_FAILED", "the call to the contract failed"},
+	CreateContractFailed:    {"CREATE_CONTRACT_FAILED", "the contract could not be created"},
+	JsonOutputCorrupted:     {"JSON_OUTPUT_CORRUPTED", "the interpreter output is not valid JSON"},
+	ContractNotExist:        {"CONTRACT_NOT_EXIST", "the recipient contract does not exist"},
+	StateCorrupted:          {"STATE_CORRUPTED", "the contract state is corrupted"},
+	LogEntryInstallFailed:   {"LOG_ENTRY_INSTALL_FAILED", "an event could not be logged"},
+	MessageCorrupted:        {"MESSAGE_CORRUPTED", "a message sent by the contract is corrupted"},
+	ReceiptIsNull:           {"RECEIPT_IS_NULL", "the receipt is empty"},
+	MaxEdgesReached:         {"MAX_EDGES_REACHED", "the transaction sent too many messages"},
+	ChainCallDiffShard:      {"CHAIN_CALL_DIFF_SHARD", "a message was sent to a contract in another shard"},
+	PreparationFailed:       {"PREPARATION_FAILED", "the inputs of the interpreter could not be prepared"},
+	NoOutput:                {"NO_OUTPUT", "the interpreter returned no output"},
+	OutputIllegal:           {"OUTPUT_ILLEGAL", "the interpreter output is illegal"},
+	MapDepthMissing:         {"MAP_DEPTH_MISSING", "the depth of a map field is unknown"},
+	GasNotSufficient:        {"GAS_NOT_SUFFICIENT", "the gas limit is too low"},
+	InternalError:           {"INTERNAL_ERROR", "the node failed internally"},
+	LibraryAsRecipient:      {"LIBRARY_AS_RECIPIENT", "a library cannot receive messages"},
+	VersionInconsistent:     {"VERSION_INCONSISTENT", "the Scilla versions of the contract and its libraries differ"},
+	LibraryExtractionFailed: {"LIBRARY_EXTRACTION_FAILED", "the libraries of the contract could not be extracted"},
+}
+
+// String returns the name the node uses for the code, such as CHECKER_FAILED.
+func (c ReceiptErrorCode) String() string {
+	if c < 0 || int(c) >= len(receiptErrorCodes) {
+		return fmt.Sprintf("RECEIPT_ERROR_%d", int(c))
+	}
+	return receiptErrorCodes[c].name
+}
+
+// Description explains the code in a few words.
+func (c ReceiptErrorCode) Description() string {
+	if c < 0 || int(c) >= len(receiptErrorCodes) {
+		return "unknown receipt error"
+	}
+	return receiptErrorCodes[c].description
+}
+
+// ReceiptException is an exception thrown by a contract, with the line of the contract it was
+// thrown at.
+type ReceiptException struct {
+	Line    int    `json:"line"`
+	Message string `json:"message"`
+}
+
+// ReceiptError describes why a transaction failed. Errors maps the depth of the message,
+// 0 for the transaction itself, to the errors raised while processing it.
+type ReceiptError struct {
+	Errors     map[int][]ReceiptErrorCode
+	Exceptions []ReceiptException
+}
+
+// NewReceiptError collects the errors and exceptions of the receipt of a failed transaction.
+func NewReceiptError(errors map[int][]ReceiptErrorCode, exceptions []ReceiptException) *ReceiptError {
+	return &ReceiptError{Errors: errors, Exceptions: exceptions}
+}
+
+func (e *ReceiptError) Error() string {
+	depths := make([]int, 0, len(e.Errors))
+	for depth := range e.Errors {
+		depths = append(depths, depth)
+	}
+	sort.Ints(depths)
+
+	var reasons []string
+	for _, depth := range depths {
+		for _, code := range e.Errors[depth] {
+			reasons = append(reasons, fmt.Sprintf("%s at depth %d (%s)", code, depth, code.Description()))
+		}
+	}
+	for _, exception := range e.Exceptions {
+		reasons = append(reasons, fmt.Sprintf("exception at line %d: %s", exception.Line, exception.Message))
+	}
+	if len(reasons) == 0 {
+		return ErrTxnFailed.Error()
+	}
+	return ErrTxnFailed.Error() + ": " + strings.Join(reasons, "; ")
+}
+
+func (e *ReceiptError) Is(target error) bool {
+	return target == ErrTxnFailed
+}
+
+// Has reports whether code was raised at any depth.
+func (e *ReceiptError) Has(code ReceiptErrorCode) bool {
+	for _, codes := range e.Errors {
+		for _, c := range codes {
+			if c == code {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// Err returns nil if the transaction succeeded and a *ReceiptError describing why it failed
+// otherwise.
+func (r *Receipt) Err() error {
+	if r.Success {
+		return nil
+	}
+	return NewReceiptError(r.Errors, r.Exceptions)
+}
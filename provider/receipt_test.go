@@ -0,0 +1,56 @@
+/*
+ * Copyright (C) 2019 Zilliqa
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+package provider
+
+import (
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestReceipt_Err(t *testing.T) {
+	rsp := newResponse(t, `{"id":1,"jsonrpc":"2.0","result":{"ID":"abc","receipt":{"cumulative_gas":"525","epoch_num":"1664280","success":false,
+		"errors":{"0":[7],"1":[1,20]},"exceptions":[{"line":86,"message":"Exception thrown: (Message [(code : (Int32 -2))])"}]}}}`)
+	tx, err := ParseTransaction(rsp)
+	assert.Nil(t, err, err)
+	assert.Equal(t, []ReceiptErrorCode{CallContractFailed}, tx.Receipt.Errors[0])
+	assert.Equal(t, 86, tx.Receipt.Exceptions[0].Line)
+
+	err = tx.Receipt.Err()
+	assert.True(t, errors.Is(err, ErrTxnFailed))
+	assert.EqualError(t, err, "transaction failed: "+
+		"CALL_CONTRACT_FAILED at depth 0 (the call to the contract failed); "+
+		"RUNNER_FAILED at depth 1 (the Scilla interpreter failed to run the contract); "+
+		"MAP_DEPTH_MISSING at depth 1 (the depth of a map field is unknown); "+
+		"exception at line 86: Exception thrown: (Message [(code : (Int32 -2))])")
+	var receiptErr *ReceiptError
+	assert.True(t, errors.As(err, &receiptErr))
+	assert.True(t, receiptErr.Has(MapDepthMissing))
+	assert.False(t, receiptErr.Has(GasNotSufficient))
+
+	tx.Receipt.Success = true
+	assert.Nil(t, tx.Receipt.Err())
+}
+
+func TestReceiptErrorCode_String(t *testing.T) {
+	assert.Equal(t, "CHECKER_FAILED", CheckerFailed.String())
+	assert.Equal(t, "NO_GAS_REMAINING_FOUND", NoGasRemainingFound.String())
+	assert.Equal(t, "LIBRARY_EXTRACTION_FAILED", LibraryExtractionFailed.String())
+	assert.Equal(t, "RECEIPT_ERROR_99", ReceiptErrorCode(99).String())
+	assert.Equal(t, "unknown receipt error", ReceiptErrorCode(-1).Description())
+	assert.EqualError(t, NewReceiptError(nil, nil), "transaction failed")
+}
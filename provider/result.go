@@ -0,0 +1,145 @@
+/*
+ * Copyright (C) 2019 Zilliqa
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+package provider
+
+import "math/big"
+
+type BlockchainInfo struct {
+	CurrentDSEpoch    string
+	CurrentMiniEpoch  string
+	DSBlockRate       float64
+	NumDSBlocks       string
+	NumPeers          uint32
+	NumTransactions   string
+	NumTxBlocks       string
+	NumTxnsDSEpoch    string
+	NumTxnsTxEpoch    string
+	ShardingStructure ShardingStructure
+	TransactionRate   float64
+	TxBlockRate       float64
+}
+
+type Balance struct {
+	Balance *big.Int
+	Nonce   uint64
+}
+
+type TransactionResult struct {
+	ID           string  `json:"ID"`
+	Version      string  `json:"version"`
+	Nonce        string  `json:"nonce"`
+	Amount       string  `json:"amount"`
+	GasPrice     string  `json:"gasPrice"`
+	GasLimit     string  `json:"gasLimit"`
+	Signature    string  `json:"signature"`
+	SenderPubKey string  `json:"senderPubKey"`
+	ToAddr       string  `json:"toAddr"`
+	Code         string  `json:"code"`
+	Data         string  `json:"data"`
+	Receipt      Receipt `json:"receipt"`
+}
+
+type Receipt struct {
+	Accepted      bool          `json:"accepted"`
+	Success       bool          `json:"success"`
+	CumulativeGas string        `json:"cumulative_gas"`
+	EpochNum      string        `json:"epoch_num"`
+	EventLogs     []interface{} `json:"event_logs"`
+	Transitions   []interface{} `json:"transitions"`
+	// Errors and Exceptions tell why a transaction failed; see Err.
+	Errors     map[int][]ReceiptErrorCode `json:"errors,omitempty"`
+	Exceptions []ReceiptException         `json:"exceptions,omitempty"`
+
+	// Events and TypedTransitions are EventLogs and Transitions decoded into typed values.
+	Events           []*EventLog   `json:"-"`
+	TypedTransitions []*Transition `json:"-"`
+}
+
+type CreateTxResult struct {
+	ContractAddress string
+	Info            string
+	TranID          string
+}
+
+type RecentTransactions struct {
+	TxnHashes []string
+	Number    uint64 `json:"number"`
+}
+
+type PendingTxnStatus struct {
+	Code      int    `json:"code"`
+	Confirmed bool   `json:"confirmed"`
+	Info      string `json:"info"`
+}
+
+type PendingTxns struct {
+	Txns []*PendingTxn
+}
+
+type PendingTxn struct {
+	TxnHash string
+	Code    int `json:"code"`
+}
+
+type SmartContract struct {
+	Address string                 `json:"address"`
+	State   map[string]interface{} `json:"state"`
+}
+
+type NodeVersion struct {
+	Commit  string
+	Version string
+}
+
+// TransactionStatus is the status of a transaction as tracked by the node. Status is 1 when the
+// transaction was dispatched to the network, 2 when it is soft-confirmed, 3 when it is confirmed,
+// 4 to 6 while it is pending and 10 or above when it was rejected.
+type TransactionStatus struct {
+	ID                string `json:"ID"`
+	Amount            string `json:"amount"`
+	Data              string `json:"data"`
+	EpochInserted     string `json:"epochInserted"`
+	EpochUpdated      string `json:"epochUpdated"`
+	GasLimit          string `json:"gasLimit"`
+	GasPrice          string `json:"gasPrice"`
+	LastModified      string `json:"lastModified"`
+	ModificationState int    `json:"modificationState"`
+	Nonce             string `json:"nonce"`
+	SenderAddr        string `json:"senderAddr"`
+	Signature         string `json:"signature"`
+	Status            int    `json:"status"`
+	Success           bool   `json:"success"`
+	ToAddr            string `json:"toAddr"`
+	Version           string `json:"version"`
+}
+
+type TxHashPage struct {
+	CurrPage     uint64
+	NumPages     uint64
+	Transactions [][]string
+}
+
+type TxnBodiesPage struct {
+	CurrPage     uint64
+	NumPages     uint64
+	Transactions []*TransactionResult
+}
+
+type StateProof struct {
+	AccountProof []string `json:"accountProof"`
+	StateProof   []string `json:"stateProof"`
+}
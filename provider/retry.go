@@ -0,0 +1,221 @@
+/*
+ * Copyright (C) 2019 Zilliqa
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+package provider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand"
+	"net"
+	"sync"
+	"syscall"
+	"time"
+)
+
+// RetryPolicy controls how often and how fast idempotent calls are sent again after a
+// transport failure. Calls that change state, such as CreateTransaction, are never retried.
+type RetryPolicy struct {
+	// MaxAttempts is the total number of attempts per call, including the first one.
+	MaxAttempts int
+	// BaseDelay is the upper bound of the wait before the first retry. It doubles for every
+	// further retry, up to MaxDelay; the actual wait is picked at random below that bound.
+	BaseDelay time.Duration
+	MaxDelay  time.Duration
+}
+
+var DefaultRetryPolicy = RetryPolicy{
+	MaxAttempts: 3,
+	BaseDelay:   200 * time.Millisecond,
+	MaxDelay:    5 * time.Second,
+}
+
+// backoff returns the wait before the given retry, using exponential backoff with full jitter.
+func (r RetryPolicy) backoff(retry int) time.Duration {
+	if r.BaseDelay <= 0 {
+		return 0
+	}
+	d := r.BaseDelay
+	for i := 1; i < retry && (r.MaxDelay <= 0 || d < r.MaxDelay); i++ {
+		d *= 2
+	}
+	if r.MaxDelay > 0 && d > r.MaxDelay {
+		d = r.MaxDelay
+	}
+	return time.Duration(rand.Int63n(int64(d) + 1))
+}
+
+// FailoverStrategy decides which host a call is sent to first when fallback hosts are configured.
+type FailoverStrategy int
+
+const (
+	// FailoverOrdered sends every call to the first healthy host, in the order the hosts were given.
+	FailoverOrdered FailoverStrategy = iota
+	// FailoverRoundRobin spreads calls over all healthy hosts.
+	FailoverRoundRobin
+)
+
+// endpointCooldown is how long a host is avoided after a failure. It doubles with every
+// consecutive failure, up to maxEndpointCooldown.
+const (
+	endpointCooldown    = 5 * time.Second
+	maxEndpointCooldown = 2 * time.Minute
+)
+
+type endpoint struct {
+	transport *transport
+	// failures counts consecutive failures; the host is considered unhealthy until retryAt.
+	failures int
+	retryAt  time.Time
+}
+
+// endpointPool keeps the health of every host a provider may send calls to.
+type endpointPool struct {
+	mu        sync.Mutex
+	endpoints []*endpoint
+	strategy  FailoverStrategy
+	next      int
+}
+
+func newEndpointPool(transports []*transport, strategy FailoverStrategy) *endpointPool {
+	pool := &endpointPool{strategy: strategy}
+	for _, t := range transports {
+		pool.endpoints = append(pool.endpoints, &endpoint{transport: t})
+	}
+	return pool
+}
+
+// pick returns the host for the next attempt. Healthy hosts not yet tried for this call come
+// first, then the unhealthy one that recovers soonest. Once every host was tried, they are
+// tried again.
+func (pool *endpointPool) pick(tried map[*endpoint]bool) *endpoint {
+	pool.mu.Lock()
+	defer pool.mu.Unlock()
+
+	if len(tried) >= len(pool.endpoints) {
+		for e := range tried {
+			delete(tried, e)
+		}
+	}
+
+	start := 0
+	if pool.strategy == FailoverRoundRobin {
+		start = pool.next
+		pool.next = (pool.next + 1) % len(pool.endpoints)
+	}
+
+	now := time.Now()
+	var best *endpoint
+	for i := 0; i < len(pool.endpoints); i++ {
+		e := pool.endpoints[(start+i)%len(pool.endpoints)]
+		if tried[e] {
+			continue
+		}
+		if !e.retryAt.After(now) {
+			best = e
+			break
+		}
+		if best == nil || e.retryAt.Before(best.retryAt) {
+			best = e
+		}
+	}
+	tried[best] = true
+	return best
+}
+
+func (pool *endpointPool) success(e *endpoint) {
+	pool.mu.Lock()
+	defer pool.mu.Unlock()
+	e.failures = 0
+	e.retryAt = time.Time{}
+}
+
+func (pool *endpointPool) failure(e *endpoint) {
+	pool.mu.Lock()
+	defer pool.mu.Unlock()
+	e.failures++
+	cooldown := endpointCooldown
+	for i := 1; i < e.failures && cooldown < maxEndpointCooldown; i++ {
+		cooldown *= 2
+	}
+	if cooldown > maxEndpointCooldown {
+		cooldown = maxEndpointCooldown
+	}
+	e.retryAt = time.Now().Add(cooldown)
+}
+
+// do runs fn against the hosts of the provider, within the limits set for every one of classes.
+// Idempotent calls that fail with a retryable error are attempted again, after a backoff and
+// preferably on another host, until the retry policy is exhausted or ctx is done. Other calls
+// are attempted once. If ctx is done while waiting to retry, ctx.Err() is returned, with the
+// last error in its message.
+func (provider *Provider) do(ctx context.Context, idempotent bool, classes []MethodClass, fn func(ctx context.Context, t *transport) error) error {
+	attempts := 1
+	if idempotent && provider.retry.MaxAttempts > 1 {
+		attempts = provider.retry.MaxAttempts
+	}
+
+	tried := make(map[*endpoint]bool)
+	var err error
+	for attempt := 0; attempt < attempts; attempt++ {
+		if attempt > 0 {
+			timer := time.NewTimer(provider.retry.backoff(attempt))
+			select {
+			case <-ctx.Done():
+				timer.Stop()
+				return fmt.Errorf("%w (last error: %v)", ctx.Err(), err)
+			case <-timer.C:
+			}
+		}
+
+		var release func()
+		if release, err = provider.acquire(ctx, classes); err != nil {
+			return err
+		}
+		e := provider.endpoints.pick(tried)
+		err = fn(ctx, e.transport)
+		release()
+		if err == nil {
+			provider.endpoints.success(e)
+			return nil
+		}
+		// a call aborted by the caller says nothing about the health of the host
+		if ctx.Err() != nil || !isRetryable(err) {
+			return err
+		}
+		provider.endpoints.failure(e)
+	}
+	return err
+}
+
+// isRetryable reports whether err is a failure of the host rather than of the call itself:
+// a timeout of a single request, a temporary network error, a refused or reset connection, or
+// an HTTP 429 or 5xx status. Other errors, e.g. an invalid host, fail the same way again.
+//
+// A node answering with a 5xx status and a JSON-RPC error in the body has handled the call, so
+// the response is returned with its error as is and the call is not retried.
+func isRetryable(err error) bool {
+	var httpErr *HTTPError
+	if errors.As(err, &httpErr) {
+		return httpErr.Code == 429 || httpErr.Code >= 500
+	}
+	var netErr net.Error
+	if errors.As(err, &netErr) && (netErr.Timeout() || netErr.Temporary()) {
+		return true
+	}
+	return errors.Is(err, syscall.ECONNREFUSED) || errors.Is(err, syscall.ECONNRESET)
+}
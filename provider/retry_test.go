@@ -0,0 +1,166 @@
+/*
+ * Copyright (C) 2019 Zilliqa
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+package provider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/stretchr/testify/assert"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"sync/atomic"
+	"syscall"
+	"testing"
+	"time"
+)
+
+// flakyServer fails the first failures requests with a 503 and answers every other request
+// with the network id.
+func flakyServer(failures int32, count *int32) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(count, 1) <= failures {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		_, _ = w.Write([]byte(`{"id":0,"jsonrpc":"2.0","result":"333"}`))
+	}))
+}
+
+var testRetryPolicy = RetryPolicy{MaxAttempts: 3, BaseDelay: time.Millisecond, MaxDelay: 5 * time.Millisecond}
+
+func TestProvider_Retry(t *testing.T) {
+	var count int32
+	server := flakyServer(2, &count)
+	defer server.Close()
+
+	provider := NewProviderWithOptions(server.URL, WithRetry(testRetryPolicy))
+	id, err := provider.GetNetworkIdTyped()
+	assert.Nil(t, err, err)
+	assert.Equal(t, "333", id)
+	assert.Equal(t, int32(3), count)
+
+	count = 0
+	_, err = NewProvider(server.URL).GetNetworkId()
+	assert.IsType(t, &HTTPError{}, err)
+	assert.Equal(t, int32(1), count)
+}
+
+func TestProvider_RetryCanceled(t *testing.T) {
+	var count int32
+	server := flakyServer(10, &count)
+	defer server.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	provider := NewProviderWithOptions(server.URL, WithRetry(RetryPolicy{MaxAttempts: 3, BaseDelay: time.Second, MaxDelay: time.Second}))
+	start := time.Now()
+	_, err := provider.WithContext(ctx).GetNetworkId()
+	assert.True(t, errors.Is(err, context.DeadlineExceeded), err)
+	assert.Contains(t, err.Error(), "503")
+	assert.Equal(t, int32(1), count)
+	assert.True(t, time.Since(start) < time.Second)
+}
+
+func TestProvider_RPCErrorNotRetried(t *testing.T) {
+	var count int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&count, 1)
+		w.WriteHeader(http.StatusInternalServerError)
+		_, _ = w.Write([]byte(`{"id":0,"jsonrpc":"2.0","error":{"code":-32603,"message":"Internal error"}}`))
+	}))
+	defer server.Close()
+
+	provider := NewProviderWithOptions(server.URL, WithRetry(testRetryPolicy))
+	rsp, err := provider.GetNetworkId()
+	assert.Nil(t, err, err)
+	assert.Equal(t, -32603, rsp.Error.Code)
+	assert.Equal(t, int32(1), count)
+}
+
+func TestIsRetryable(t *testing.T) {
+	dial := func(err error) error {
+		return fmt.Errorf("rpc call: %w", &url.Error{Op: "Post", URL: "http://localhost", Err: &net.OpError{
+			Op: "dial", Net: "tcp", Err: os.NewSyscallError("connect", err)}})
+	}
+	assert.True(t, isRetryable(dial(syscall.ECONNREFUSED)))
+	assert.True(t, isRetryable(dial(syscall.ECONNRESET)))
+	assert.True(t, isRetryable(&url.Error{Op: "Post", URL: "http://localhost", Err: &net.DNSError{IsTimeout: true}}))
+	assert.True(t, isRetryable(newHTTPError(503, errors.New("unavailable"))))
+	assert.True(t, isRetryable(newHTTPError(429, errors.New("too many requests"))))
+
+	assert.False(t, isRetryable(newHTTPError(404, errors.New("not found"))))
+	assert.False(t, isRetryable(&url.Error{Op: "Post", URL: "ftp://localhost", Err: errors.New("unsupported protocol scheme")}))
+	assert.False(t, isRetryable(&url.Error{Op: "Post", URL: "http://unknown", Err: &net.DNSError{Err: "no such host", IsNotFound: true}}))
+}
+
+func TestProvider_CreateTransactionNotRetried(t *testing.T) {
+	var count int32
+	server := flakyServer(1, &count)
+	defer server.Close()
+
+	provider := NewProviderWithOptions(server.URL, WithRetry(testRetryPolicy))
+	_, err := provider.CreateTransaction(TransactionPayload{})
+	assert.NotNil(t, err)
+	assert.Equal(t, int32(1), count)
+}
+
+func TestProvider_Failover(t *testing.T) {
+	var downCount, upCount int32
+	down := flakyServer(100, &downCount)
+	defer down.Close()
+	up := flakyServer(0, &upCount)
+	defer up.Close()
+
+	provider := NewProviderWithOptions(down.URL, WithFallbackHosts(up.URL))
+	_, err := provider.GetNetworkIdTyped()
+	assert.Nil(t, err, err)
+	assert.Equal(t, int32(1), downCount)
+	assert.Equal(t, int32(1), upCount)
+
+	// the failed host is avoided until its cooldown is over
+	_, err = provider.GetNetworkIdTyped()
+	assert.Nil(t, err, err)
+	assert.Equal(t, int32(1), downCount)
+	assert.Equal(t, int32(2), upCount)
+}
+
+func TestProvider_FailoverRoundRobin(t *testing.T) {
+	var count1, count2 int32
+	server1 := flakyServer(0, &count1)
+	defer server1.Close()
+	server2 := flakyServer(0, &count2)
+	defer server2.Close()
+
+	provider := NewProviderWithOptions(server1.URL, WithFallbackHosts(server2.URL), WithFailoverStrategy(FailoverRoundRobin))
+	for i := 0; i < 4; i++ {
+		_, err := provider.GetNetworkIdTyped()
+		assert.Nil(t, err, err)
+	}
+	assert.Equal(t, int32(2), count1)
+	assert.Equal(t, int32(2), count2)
+}
+
+func TestRetryPolicy_backoff(t *testing.T) {
+	policy := RetryPolicy{BaseDelay: 100 * time.Millisecond, MaxDelay: 300 * time.Millisecond}
+	for retry := 1; retry < 10; retry++ {
+		assert.True(t, policy.backoff(retry) <= 300*time.Millisecond)
+	}
+}
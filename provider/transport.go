@@ -0,0 +1,149 @@
+/*
+ * Copyright (C) 2019 Zilliqa
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+package provider
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/ybbus/jsonrpc"
+	"io/ioutil"
+	"net/http"
+	"time"
+)
+
+// transport posts JSON-RPC requests to a single host. Unlike jsonrpc.RPCClient,
+// every request carries a context, so deadlines and cancellation reach the HTTP layer.
+type transport struct {
+	host       string
+	httpClient *http.Client
+	headers    map[string]string
+	timeout    time.Duration
+}
+
+// post sends body to the host and returns the raw response body together with the HTTP status code.
+func (t *transport) post(ctx context.Context, body []byte) ([]byte, int, error) {
+	if t.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, t.timeout)
+		defer cancel()
+	}
+	request, err := http.NewRequestWithContext(ctx, "POST", t.host, bytes.NewReader(body))
+	if err != nil {
+		return nil, 0, err
+	}
+	request.Header.Set("Content-Type", "application/json")
+	request.Header.Set("Accept", "application/json")
+	// custom headers are set last, so that even content type and accept can be overwritten
+	for k, v := range t.headers {
+		request.Header.Set(k, v)
+	}
+
+	response, err := t.httpClient.Do(request)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer response.Body.Close()
+
+	result, err := ioutil.ReadAll(response.Body)
+	if err != nil {
+		return nil, response.StatusCode, err
+	}
+	return result, response.StatusCode, nil
+}
+
+// call sends request and records the sizes of the request and response bodies in record.
+func (t *transport) call(ctx context.Context, request *jsonrpc.RPCRequest, record *Call) (*jsonrpc.RPCResponse, error) {
+	body, err := json.Marshal(request)
+	if err != nil {
+		return nil, fmt.Errorf("rpc call %s() on %s: %s", request.Method, t.host, err)
+	}
+	result, status, err := t.post(ctx, body)
+	record.RequestSize, record.ResponseSize = len(body), len(result)
+	if err != nil {
+		return nil, fmt.Errorf("rpc call %s() on %s: %w", request.Method, t.host, err)
+	}
+
+	var response *jsonrpc.RPCResponse
+	if err := decodeResponse(result, &response); err != nil {
+		return nil, newHTTPError(status, fmt.Errorf("rpc call %s() on %s status code: %d. could not decode body to rpc response: %s",
+			request.Method, t.host, status, err))
+	}
+	if response == nil {
+		return nil, newHTTPError(status, fmt.Errorf("rpc call %s() on %s status code: %d. rpc response missing",
+			request.Method, t.host, status))
+	}
+	return response, nil
+}
+
+// decodeResponse decodes JSON-RPC response bytes the same way jsonrpc.RPCClient does,
+// keeping numbers as json.Number.
+func decodeResponse(data []byte, out interface{}) error {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	return decoder.Decode(out)
+}
+
+func (t *transport) batchCall(ctx context.Context, requests []*jsonrpc.RPCRequest) ([]*jsonrpc.RPCResponse, error) {
+	body, err := json.Marshal(requests)
+	if err != nil {
+		return nil, fmt.Errorf("rpc batch call on %s: %s", t.host, err)
+	}
+	result, status, err := t.post(ctx, body)
+	if err != nil {
+		return nil, fmt.Errorf("rpc batch call on %s: %w", t.host, err)
+	}
+
+	var responses []*jsonrpc.RPCResponse
+	if err := decodeResponse(result, &responses); err != nil {
+		// a node rejecting the whole batch answers with a single error object
+		var response *jsonrpc.RPCResponse
+		if decodeResponse(result, &response) == nil && response != nil && response.Error != nil {
+			return nil, fmt.Errorf("rpc batch call on %s: %w", t.host, NewRPCError(response.Error))
+		}
+		return nil, newHTTPError(status, fmt.Errorf("rpc batch call on %s status code: %d. could not decode body to rpc response: %s",
+			t.host, status, err))
+	}
+	if len(responses) == 0 {
+		return nil, newHTTPError(status, fmt.Errorf("rpc batch call on %s status code: %d. rpc response missing", t.host, status))
+	}
+	return responses, nil
+}
+
+// HTTPError is returned when the node answers with an HTTP error status and a body that is
+// not a JSON-RPC response.
+type HTTPError struct {
+	Code int
+	err  error
+}
+
+func (e *HTTPError) Error() string {
+	return e.err.Error()
+}
+
+func (e *HTTPError) Unwrap() error {
+	return e.err
+}
+
+// newHTTPError wraps err into an *HTTPError if status is an HTTP error status.
+func newHTTPError(status int, err error) error {
+	if status >= 400 {
+		return &HTTPError{Code: status, err: err}
+	}
+	return err
+}
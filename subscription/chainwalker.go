@@ -18,34 +18,39 @@ package subscription
 
 import (
 	"context"
-	"fmt"
+	"errors"
+	"github.com/Zilliqa/gozilliqa-sdk/logging"
 	"github.com/Zilliqa/gozilliqa-sdk/provider"
-	"github.com/Zilliqa/gozilliqa-sdk/workpool"
 	"strconv"
 	"strings"
 	"sync"
-	"time"
 )
 
 type Walker struct {
-	Provider     *provider.Provider
+	Provider     provider.Reader
 	FromBlock    uint64
 	ToBlock      uint64
 	CurrentBlock uint64
 	Address      string
 	EventLogs    map[uint64]Log
+	// Deprecated: WorkerNumber is not used, transactions are fetched with batch requests.
 	WorkerNumber int64
 	EventName    string
+	// Logger receives the errors met while walking. If nil, the logger of Provider is used.
+	Logger logging.Logger
 }
 
 type Log struct {
 	Hash      string
 	EventName string
 	Address   string
-	Logs      interface{}
+	// Logs is the event log as raw JSON value, Event the same log decoded.
+	Logs  interface{}
+	Event *provider.EventLog
 }
 
-func NewWalker(p *provider.Provider, from, to uint64, address string, workerNumber int64, eventName string) *Walker {
+// NewWalker creates a walker over the blocks [from, to). workerNumber is not used anymore.
+func NewWalker(p provider.Reader, from, to uint64, address string, workerNumber int64, eventName string) *Walker {
 	eventLogs := make(map[uint64]Log)
 	return &Walker{
 		Provider:     p,
@@ -58,14 +63,21 @@ func NewWalker(p *provider.Provider, from, to uint64, address string, workerNumb
 	}
 }
 
+// GetEventReceiptTask collects the event logs of one transaction for a Walker.
+//
+// Deprecated: the walker does not run tasks anymore; StartTraversalBlockWithContext fetches
+// the transactions itself.
 type GetEventReceiptTask struct {
-	Provider *provider.Provider
+	Provider provider.Reader
 	Id       string
 	Complete *Complete
 	Walker   *Walker
 	BlockNum uint64
 }
 
+// Complete counts the tasks that ran.
+//
+// Deprecated: only used by GetEventReceiptTask.
 type Complete struct {
 	sync.Mutex
 	Number int
@@ -75,7 +87,8 @@ func (t GetEventReceiptTask) UUID() string {
 	return t.Id
 }
 
-func NewGetReceiptTask(tx string, provider2 *provider.Provider, c *Complete, w *Walker, b uint64) GetEventReceiptTask {
+// Deprecated: see GetEventReceiptTask.
+func NewGetReceiptTask(tx string, provider2 provider.Reader, c *Complete, w *Walker, b uint64) GetEventReceiptTask {
 	return GetEventReceiptTask{
 		Id:       tx,
 		Provider: provider2,
@@ -89,88 +102,130 @@ func (t GetEventReceiptTask) Run() {
 	t.Complete.Lock()
 	defer t.Complete.Unlock()
 	t.Complete.Number++
-	rsp, _ := t.Provider.GetTransaction(t.Id)
-	resultMap := rsp.Result.(map[string]interface{})
-	receipt := resultMap["receipt"].(map[string]interface{})
-	addr := resultMap["toAddr"].(string)
-	success, ok1 := receipt["success"]
-	if success == nil || success.(bool) == false {
+	_ = t.Walker.fetchEventLogs(t.Provider, t.BlockNum, t.Id)
+}
+
+// fetchEventLogs reads the transaction hash with p and collects its event logs.
+func (w *Walker) fetchEventLogs(p provider.Reader, blockNum uint64, hash string) error {
+	rsp, err := p.GetTransaction(hash)
+	if rsp == nil {
+		return err
+	}
+	tx, err := provider.ParseTransaction(rsp)
+	if err != nil {
+		return err
+	}
+	w.collectEventLogs(blockNum, hash, tx)
+	return nil
+}
+
+// collectEventLogs records the event logs of tx that were emitted by the walker's contract
+// under the walker's event name.
+func (w *Walker) collectEventLogs(blockNum uint64, hash string, tx *provider.TransactionResult) {
+	if !tx.Receipt.Success {
 		return
-	} else {
-		eventLogs, ok := receipt["event_logs"]
-		if ok {
-			els := eventLogs.([]interface{})
-			for _, el := range els {
-				log := el.(map[string]interface{})
-				eventName, ok2 := log["_eventname"]
-				// important: currently we only compare contract address to toAddr
-				if ok1 && ok2 && strings.Compare(strings.ToLower(addr), strings.ToLower(t.Walker.Address[2:])) == 0 && strings.Compare(eventName.(string), t.Walker.EventName) == 0 {
-					logData := Log{
-						Hash:      t.Id,
-						EventName: eventName.(string),
-						Address:   t.Walker.Address,
-						Logs:      log,
-					}
-					t.Walker.EventLogs[t.BlockNum] = logData
-				}
-			}
+	}
+	// important: currently we only compare contract address to toAddr
+	if strings.Compare(strings.ToLower(tx.ToAddr), strings.ToLower(w.Address[2:])) != 0 {
+		return
+	}
+	for i, event := range tx.Receipt.Events {
+		if event == nil || strings.Compare(event.EventName, w.EventName) != 0 {
+			continue
+		}
+		var raw interface{}
+		if i < len(tx.Receipt.EventLogs) {
+			raw = tx.Receipt.EventLogs[i]
+		}
+		w.EventLogs[blockNum] = Log{
+			Hash:      hash,
+			EventName: event.EventName,
+			Address:   w.Address,
+			Logs:      raw,
+			Event:     event,
 		}
 	}
 }
 
+func (w *Walker) logger() logging.Logger {
+	if w.Logger != nil {
+		return w.Logger
+	}
+	if l, ok := w.Provider.(interface{ Logger() logging.Logger }); ok {
+		return l.Logger()
+	}
+	return logging.Nop
+}
+
 func (w *Walker) StartTraversalBlock() {
-	for i := w.FromBlock; i < w.ToBlock; i++ {
-		rsp, err := w.Provider.GetTransactionsForTxBlock(strconv.FormatUint(i, 10))
+	_ = w.StartTraversalBlockWithContext(context.Background())
+}
 
-		if err != nil || rsp.Error != nil {
-			if err != nil {
-				fmt.Println("tx for block ", i, " = ", err)
-			} else {
-				fmt.Println("tx for block ", i, " = ", rsp.Error)
+// StartTraversalBlockWithContext visits the blocks in [FromBlock, ToBlock) and collects the matching
+// event logs into EventLogs. With a *provider.Provider the transactions of each block are fetched with
+// batch requests, so a busy block costs one round trip per provider.DefaultMaxBatchSize transactions,
+// and every request is bound to ctx. Other readers are asked for one transaction at a time.
+// ctx.Err() is returned if ctx is done before all blocks are visited.
+func (w *Walker) StartTraversalBlockWithContext(ctx context.Context) error {
+	var p provider.Reader = w.Provider
+	batcher, ok := w.Provider.(*provider.Provider)
+	if ok {
+		batcher = batcher.WithContext(ctx)
+		p = batcher
+	}
+	logger := w.logger()
+	for i := w.FromBlock; i < w.ToBlock; i++ {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		w.CurrentBlock = i
+		rsp, err := p.GetTransactionsForTxBlock(strconv.FormatUint(i, 10))
+		if err != nil {
+			logger.Error("get transactions for block", "block", i, "error", err)
+			continue
+		}
+		txResult, err := provider.ParseTxHashArray(rsp)
+		if err != nil {
+			if !errors.Is(err, provider.EmptyBlock) {
+				logger.Error("get transactions for block", "block", i, "error", err)
 			}
+			continue
+		}
 
-		} else {
-			txResult := rsp.Result.([]interface{})
-			var txns []string
-
-			// flat tx hash
-			for _, txs := range txResult {
-				if txs == nil {
-					continue
-				}
-				txList := txs.([]interface{})
-				if len(txList) > 0 {
-					for _, tx := range txList {
-						txns = append(txns, tx.(string))
+		if batcher == nil {
+			for _, txList := range txResult {
+				for _, tx := range txList {
+					if err := ctx.Err(); err != nil {
+						return err
+					}
+					if err := w.fetchEventLogs(p, i, tx); err != nil {
+						logger.Error("get transaction details for block", "block", i, "hash", tx, "error", err)
 					}
-				} else {
-					continue
 				}
 			}
+			continue
+		}
 
-			// get detail
-			fmt.Println("tx for block ", i, " = ", txns)
-			totalTxNumber := len(txns)
-			complete := &Complete{
-				Number: 0,
+		// flat tx hash
+		batch := batcher.NewBatch()
+		for _, txList := range txResult {
+			for _, tx := range txList {
+				batch.GetTransaction(tx)
 			}
+		}
+		if batch.Len() == 0 {
+			continue
+		}
 
-			wp := workpool.NewWorkPool(w.WorkerNumber)
-			quit := make(chan struct{})
-			for _, tx := range txns {
-				task := NewGetReceiptTask(tx, w.Provider, complete, w, i)
-				wp.AddTask(task)
+		// get detail, a failure to send is carried by the results of the calls it failed
+		results, _ := batch.Send()
+		for _, result := range results {
+			if result.Err != nil {
+				logger.Error("get transaction details for block", "block", i, "hash", result.Params[0], "error", result.Err)
+				continue
 			}
-			go func(q chan struct{}) {
-				wp.Poll(context.TODO(), quit)
-				for {
-					time.Sleep(time.Second)
-					if totalTxNumber == complete.Number {
-						close(q)
-						break
-					}
-				}
-			}(quit)
+			w.collectEventLogs(i, result.Params[0].(string), result.Result.(*provider.TransactionResult))
 		}
 	}
+	return nil
 }
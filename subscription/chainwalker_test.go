@@ -17,8 +17,18 @@
 package subscription
 
 import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"github.com/Zilliqa/gozilliqa-sdk/logging"
 	provider2 "github.com/Zilliqa/gozilliqa-sdk/provider"
+	"github.com/stretchr/testify/assert"
+	"github.com/ybbus/jsonrpc"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
 	"os"
+	"strings"
 	"testing"
 )
 
@@ -31,3 +41,130 @@ func TestWalker_TraversalBlock(t *testing.T) {
 	walker.StartTraversalBlock()
 	t.Log(walker.EventLogs)
 }
+
+func TestWalker_TraversalBlockBatch(t *testing.T) {
+	batches := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := ioutil.ReadAll(r.Body)
+		if !bytes.HasPrefix(body, []byte("[")) {
+			// GetTransactionsForTxBlock
+			_, _ = w.Write([]byte(`{"id":0,"jsonrpc":"2.0","result":[["h1","h2"],null,["h3"]]}`))
+			return
+		}
+		batches++
+		var requests []struct {
+			ID     int      `json:"id"`
+			Params []string `json:"params"`
+		}
+		_ = json.Unmarshal(body, &requests)
+		responses := make([]interface{}, 0)
+		for _, req := range requests {
+			eventName := "Mint"
+			if req.Params[0] == "h2" {
+				eventName = "Transfer"
+			}
+			responses = append(responses, map[string]interface{}{
+				"id":      req.ID,
+				"jsonrpc": "2.0",
+				"result": map[string]interface{}{
+					"ID":     req.Params[0],
+					"toAddr": "ab14b0fd133721d7c47ef410908e8ffc2b39167f",
+					"receipt": map[string]interface{}{
+						"success":    true,
+						"event_logs": []interface{}{map[string]interface{}{"_eventname": eventName, "params": []interface{}{}}},
+					},
+				},
+			})
+		}
+		_ = json.NewEncoder(w).Encode(responses)
+	}))
+	defer server.Close()
+
+	provider := provider2.NewProvider(server.URL)
+	walker := NewWalker(provider, 10, 12, "0xab14b0fd133721d7c47ef410908e8ffc2b39167f", 50, "Transfer")
+	walker.StartTraversalBlock()
+	assert.Equal(t, 2, batches)
+	assert.Equal(t, 2, len(walker.EventLogs))
+	assert.Equal(t, "h2", walker.EventLogs[10].Hash)
+}
+
+func TestWalker_TraversalBlockBatchError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := ioutil.ReadAll(r.Body)
+		if !bytes.HasPrefix(body, []byte("[")) {
+			_, _ = w.Write([]byte(`{"id":0,"jsonrpc":"2.0","result":[["h1","h2"]]}`))
+			return
+		}
+		var requests []struct {
+			ID     int      `json:"id"`
+			Params []string `json:"params"`
+		}
+		_ = json.Unmarshal(body, &requests)
+		responses := make([]interface{}, 0)
+		for _, req := range requests {
+			if req.Params[0] == "h1" {
+				responses = append(responses, map[string]interface{}{
+					"id":      req.ID,
+					"jsonrpc": "2.0",
+					"error":   map[string]interface{}{"code": -20, "message": "Txn Hash not Present"},
+				})
+				continue
+			}
+			responses = append(responses, map[string]interface{}{
+				"id":      req.ID,
+				"jsonrpc": "2.0",
+				"result": map[string]interface{}{
+					"ID":     req.Params[0],
+					"toAddr": "ab14b0fd133721d7c47ef410908e8ffc2b39167f",
+					"receipt": map[string]interface{}{
+						"success":    true,
+						"event_logs": []interface{}{map[string]interface{}{"_eventname": "Transfer", "params": []interface{}{}}},
+					},
+				},
+			})
+		}
+		_ = json.NewEncoder(w).Encode(responses)
+	}))
+	defer server.Close()
+
+	var out bytes.Buffer
+	walker := NewWalker(provider2.NewProvider(server.URL), 10, 11, "0xab14b0fd133721d7c47ef410908e8ffc2b39167f", 50, "Transfer")
+	walker.Logger = logging.NewTextLogger(&out, logging.LevelError)
+	assert.Nil(t, walker.StartTraversalBlockWithContext(context.Background()))
+	assert.Equal(t, 1, len(walker.EventLogs))
+	assert.Equal(t, "h2", walker.EventLogs[10].Hash)
+	assert.Equal(t, 1, strings.Count(out.String(), "get transaction details for block"))
+	assert.True(t, strings.Contains(out.String(), "hash=h1"), out.String())
+	assert.True(t, strings.Contains(out.String(), "Txn Hash not Present"), out.String())
+}
+
+// blockReader serves two transactions in every block without batch support.
+type blockReader struct {
+	provider2.Reader
+	calls int
+}
+
+func (r *blockReader) GetTransactionsForTxBlock(txBlockNumber string) (*jsonrpc.RPCResponse, error) {
+	return &jsonrpc.RPCResponse{Result: []interface{}{[]interface{}{"h1", "h2"}}}, nil
+}
+
+func (r *blockReader) GetTransaction(transactionHash string) (*jsonrpc.RPCResponse, error) {
+	r.calls++
+	return &jsonrpc.RPCResponse{Result: map[string]interface{}{
+		"ID":     transactionHash,
+		"toAddr": "ab14b0fd133721d7c47ef410908e8ffc2b39167f",
+		"receipt": map[string]interface{}{
+			"success":    true,
+			"event_logs": []interface{}{map[string]interface{}{"_eventname": "Transfer", "params": []interface{}{}}},
+		},
+	}}, nil
+}
+
+func TestWalker_TraversalBlockReader(t *testing.T) {
+	reader := &blockReader{}
+	walker := NewWalker(reader, 10, 13, "0xab14b0fd133721d7c47ef410908e8ffc2b39167f", 1, "Transfer")
+	assert.Nil(t, walker.StartTraversalBlockWithContext(context.Background()))
+	assert.Equal(t, 6, reader.calls)
+	assert.Equal(t, 3, len(walker.EventLogs))
+	assert.Equal(t, "h2", walker.EventLogs[12].Hash)
+}
@@ -16,7 +16,10 @@
  */
 package subscription
 
-import "net/url"
+import (
+	"context"
+	"net/url"
+)
 
 type EventLogSubscriber struct {
 	Ws *Websocket
@@ -36,11 +39,16 @@ func BuildEventLogSubscriber(url url.URL, addresses []string) *EventLogSubscribe
 }
 
 func (subscriber *EventLogSubscriber) Start() (error, chan error, chan []byte) {
-	err := subscriber.Ws.Subscribe()
+	return subscriber.StartWithContext(context.Background())
+}
+
+// StartWithContext subscribes and starts reading like Start, stopping once ctx is done.
+func (subscriber *EventLogSubscriber) StartWithContext(ctx context.Context) (error, chan error, chan []byte) {
+	err := subscriber.Ws.SubscribeWithContext(ctx)
 	if err != nil {
 		return err, nil, nil
 	}
 
-	subscriber.Ws.Start()
+	subscriber.Ws.StartWithContext(ctx)
 	return nil, subscriber.Ws.Err, subscriber.Ws.Msg
 }
@@ -16,7 +16,12 @@
  */
 package subscription
 
-import "net/url"
+import (
+	"context"
+	"encoding/json"
+	"net/url"
+	"strconv"
+)
 
 type NewBlockSubscriber struct {
 	Ws *Websocket
@@ -31,11 +36,76 @@ func BuildNewBlockSubscriber(url url.URL) *NewBlockSubscriber {
 }
 
 func (subscriber *NewBlockSubscriber) Start() (error, chan error, chan []byte) {
-	err := subscriber.Ws.Subscribe()
+	return subscriber.StartWithContext(context.Background())
+}
+
+// StartWithContext subscribes and starts reading like Start, stopping once ctx is done.
+func (subscriber *NewBlockSubscriber) StartWithContext(ctx context.Context) (error, chan error, chan []byte) {
+	err := subscriber.Ws.SubscribeWithContext(ctx)
 	if err != nil {
 		return err, nil, nil
 	}
 
-	subscriber.Ws.Start()
+	subscriber.Ws.StartWithContext(ctx)
 	return nil, subscriber.Ws.Err, subscriber.Ws.Msg
 }
+
+// BlockNumbers reads the messages of a NewBlockSubscriber and sends the number of every new
+// Tx block to the returned channel, e.g. for transaction.WithBlocks. Messages that are not
+// NewBlock notifications are skipped. The channel is closed once msg is closed or ctx is done.
+func BlockNumbers(ctx context.Context, msg <-chan []byte) <-chan uint64 {
+	blocks := make(chan uint64, 1)
+	go func() {
+		defer close(blocks)
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case message, ok := <-msg:
+				if !ok {
+					return
+				}
+				for _, n := range newBlockNumbers(message) {
+					select {
+					case blocks <- n:
+					case <-ctx.Done():
+						return
+					}
+				}
+			}
+		}
+	}()
+	return blocks
+}
+
+type newBlockNotification struct {
+	Type   MessageTypeT `json:"type"`
+	Values []struct {
+		Query MessageTypeT `json:"query"`
+		Value struct {
+			TxBlock struct {
+				Header struct {
+					BlockNum string `json:"BlockNum"`
+				} `json:"header"`
+			} `json:"TxBlock"`
+		} `json:"value"`
+	} `json:"values"`
+}
+
+func newBlockNumbers(message []byte) []uint64 {
+	var notification newBlockNotification
+	if err := json.Unmarshal(message, &notification); err != nil || notification.Type != Notification {
+		return nil
+	}
+	var numbers []uint64
+	for _, value := range notification.Values {
+		if value.Query != NewBlock {
+			continue
+		}
+		n, err := strconv.ParseUint(value.Value.TxBlock.Header.BlockNum, 10, 64)
+		if err == nil {
+			numbers = append(numbers, n)
+		}
+	}
+	return numbers
+}
@@ -17,6 +17,7 @@
 package subscription
 
 import (
+	"context"
 	"github.com/stretchr/testify/assert"
 	"net/url"
 	"os"
@@ -50,3 +51,17 @@ func TestBuildNewBlockSubscriber(t *testing.T) {
 	}
 
 }
+
+func TestBlockNumbers(t *testing.T) {
+	msg := make(chan []byte, 3)
+	msg <- []byte(`{"type":"Notification","values":[{"query":"NewBlock","value":{"TxBlock":{"header":{"BlockNum":"1664279"}},"TxHashes":[[]]}}]}`)
+	msg <- []byte(`{"type":"Notification","values":[{"query":"EventLog","value":[]}]}`)
+	msg <- []byte(`{"query":"NewBlock"}`)
+	close(msg)
+
+	var numbers []uint64
+	for n := range BlockNumbers(context.Background(), msg) {
+		numbers = append(numbers, n)
+	}
+	assert.Equal(t, []uint64{1664279}, numbers)
+}
@@ -17,8 +17,10 @@
 package subscription
 
 import (
+	"context"
 	"github.com/gorilla/websocket"
 	"net/url"
+	"sync"
 )
 
 type Websocket struct {
@@ -27,6 +29,8 @@ type Websocket struct {
 	Err    chan error
 	Msg    chan []byte
 	Client *websocket.Conn
+
+	mu sync.Mutex
 }
 
 func NewWebsocket(topic Topic, url url.URL, err chan error, msg chan []byte) *Websocket {
@@ -39,11 +43,16 @@ func NewWebsocket(topic Topic, url url.URL, err chan error, msg chan []byte) *We
 }
 
 func (w *Websocket) Subscribe() error {
-	c, _, err := websocket.DefaultDialer.Dial(w.URL.String(), nil)
+	return w.SubscribeWithContext(context.Background())
+}
+
+// SubscribeWithContext dials the server and sends the subscription topic, giving up when ctx is done.
+func (w *Websocket) SubscribeWithContext(ctx context.Context) error {
+	c, _, err := websocket.DefaultDialer.DialContext(ctx, w.URL.String(), nil)
 	if err != nil {
 		return err
 	}
-	w.Client = c
+	w.setClient(c)
 
 	sub, err := w.Topic.Stringify()
 	if err != nil {
@@ -59,24 +68,53 @@ func (w *Websocket) Subscribe() error {
 }
 
 func (w *Websocket) Start() {
+	w.StartWithContext(context.Background())
+}
+
+// StartWithContext reads messages like Start until ctx is done. Once ctx is done the connection
+// is closed, no reconnect is attempted and nothing more is sent to Msg or Err.
+func (w *Websocket) StartWithContext(ctx context.Context) {
+	if ctx.Done() != nil {
+		go func() {
+			<-ctx.Done()
+			_ = w.Close()
+		}()
+	}
 	go func() {
 		for {
-			_, message, err := w.Client.ReadMessage()
+			_, message, err := w.client().ReadMessage()
+			if ctx.Err() != nil {
+				return
+			}
 			if err != nil {
-				w.Err <- err
-				c, _, err := websocket.DefaultDialer.Dial(w.URL.String(), nil)
+				if !w.sendErr(ctx, err) {
+					return
+				}
+				c, _, err := websocket.DefaultDialer.DialContext(ctx, w.URL.String(), nil)
 				if err != nil {
-					w.Err <- err
+					if !w.sendErr(ctx, err) {
+						return
+					}
 				} else {
-					w.Client = c
+					w.setClient(c)
+					if ctx.Err() != nil {
+						_ = c.Close()
+						return
+					}
 					sub, _ := w.Topic.Stringify()
 					err2 := c.WriteMessage(websocket.TextMessage, sub)
 					if err2 != nil {
-						w.Err <- err2
+						if !w.sendErr(ctx, err2) {
+							return
+						}
 					}
 				}
 			} else {
-				w.Msg <- message
+				select {
+				case w.Msg <- message:
+				case <-ctx.Done():
+					return
+				}
 			}
 
 		}
@@ -84,5 +122,26 @@ func (w *Websocket) Start() {
 }
 
 func (w *Websocket) Close() error {
-	return w.Client.Close()
+	return w.client().Close()
+}
+
+func (w *Websocket) sendErr(ctx context.Context, err error) bool {
+	select {
+	case w.Err <- err:
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
+
+func (w *Websocket) client() *websocket.Conn {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.Client
+}
+
+func (w *Websocket) setClient(c *websocket.Conn) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.Client = c
 }
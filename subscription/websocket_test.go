@@ -17,10 +17,16 @@
 package subscription
 
 import (
+	"context"
+	"github.com/gorilla/websocket"
 	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
 	"net/url"
 	"os"
+	"strings"
 	"testing"
+	"time"
 )
 
 func TestWebsocket_Start(t *testing.T) {
@@ -52,3 +58,45 @@ func TestWebsocket_Start(t *testing.T) {
 
 	}
 }
+
+func TestWebsocket_StartWithContext(t *testing.T) {
+	upgrader := websocket.Upgrader{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		c, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			return
+		}
+		defer c.Close()
+		_, query, err := c.ReadMessage()
+		if err != nil {
+			return
+		}
+		for {
+			if err := c.WriteMessage(websocket.TextMessage, query); err != nil {
+				return
+			}
+			time.Sleep(10 * time.Millisecond)
+		}
+	}))
+	defer server.Close()
+
+	u := url.URL{Scheme: "ws", Host: strings.TrimPrefix(server.URL, "http://")}
+	ws := NewWebsocket(&NewBlockQuery{Query: "NewBlock"}, u, make(chan error, 1), make(chan []byte))
+	ctx, cancel := context.WithCancel(context.Background())
+	err := ws.SubscribeWithContext(ctx)
+	assert.Nil(t, err, err)
+	ws.StartWithContext(ctx)
+
+	message := <-ws.Msg
+	assert.Equal(t, `{"query":"NewBlock"}`, string(message))
+	cancel()
+
+	time.Sleep(100 * time.Millisecond)
+	select {
+	case <-ws.Msg:
+		t.Error("message received after cancel")
+	case err := <-ws.Err:
+		t.Error("error received after cancel", err)
+	default:
+	}
+}
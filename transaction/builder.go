@@ -0,0 +1,218 @@
+/*
+ * Copyright (C) 2019 Zilliqa
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+package transaction
+
+import (
+	"errors"
+	"fmt"
+	"github.com/Zilliqa/gozilliqa-sdk/provider"
+	"github.com/Zilliqa/gozilliqa-sdk/validator"
+	"math/big"
+	"strconv"
+)
+
+// DefaultTransferGasLimit is the gas limit of transfers built without WithGasLimit.
+const DefaultTransferGasLimit = 50
+
+// DefaultMsgVersion is the message version of transactions built with a Builder.
+const DefaultMsgVersion = 1
+
+type builderKind int
+
+const (
+	transferKind builderKind = iota
+	deployKind
+	callKind
+)
+
+// Builder builds the Transaction of a transfer, a contract deployment or a contract call and
+// checks it before it is signed:
+//
+//	tx, err := transaction.Transfer("zil1...", big.NewInt(1000000)).
+//		ForChain(333).
+//		WithGasPrice(big.NewInt(2000000000)).
+//		Build()
+//
+// The nonce is left empty unless WithNonce is used, so that Wallet.Sign looks it up.
+type Builder struct {
+	kind     builderKind
+	chainID  int
+	toAddr   string
+	amount   *big.Int
+	gasPrice *big.Int
+	gasLimit uint64
+	nonce    uint64
+	code     string
+	init     []provider.Value
+	tag      string
+	params   []provider.Value
+	priority bool
+}
+
+// Transfer starts a transfer of amount Qa to the address to, in hex or bech32 form.
+func Transfer(to string, amount *big.Int) *Builder {
+	return &Builder{kind: transferKind, toAddr: to, amount: amount, gasLimit: DefaultTransferGasLimit}
+}
+
+// Deploy starts the deployment of a contract with the Scilla code and init parameters.
+func Deploy(code string, init []provider.Value) *Builder {
+	return &Builder{kind: deployKind, code: code, init: init}
+}
+
+// Call starts a call of the transition tag of the contract at addr.
+func Call(addr, tag string, params []provider.Value) *Builder {
+	return &Builder{kind: callKind, toAddr: addr, tag: tag, params: params}
+}
+
+// ForChain sets the chain ID the transaction is for, e.g. 1 for the mainnet. It is required.
+func (b *Builder) ForChain(chainID int) *Builder {
+	b.chainID = chainID
+	return b
+}
+
+// WithAmount sets the amount in Qa that is sent along, e.g. with a contract call.
+func (b *Builder) WithAmount(amount *big.Int) *Builder {
+	b.amount = amount
+	return b
+}
+
+// WithGasPrice sets the gas price in Qa. It is required.
+func (b *Builder) WithGasPrice(gasPrice *big.Int) *Builder {
+	b.gasPrice = gasPrice
+	return b
+}
+
+// WithGasLimit sets the gas limit. It is required for deployments and calls; transfers use
+// DefaultTransferGasLimit otherwise.
+func (b *Builder) WithGasLimit(gasLimit uint64) *Builder {
+	b.gasLimit = gasLimit
+	return b
+}
+
+// WithNonce sets the nonce instead of having it looked up when the transaction is signed.
+func (b *Builder) WithNonce(nonce uint64) *Builder {
+	b.nonce = nonce
+	return b
+}
+
+// WithPriority marks the transaction as a priority transaction.
+func (b *Builder) WithPriority(priority bool) *Builder {
+	b.priority = priority
+	return b
+}
+
+// Build checks the transaction and returns it, ready to be signed.
+func (b *Builder) Build() (*Transaction, error) {
+	if err := b.validate(); err != nil {
+		return nil, fmt.Errorf("build transaction: %w", err)
+	}
+
+	amount := b.amount
+	if amount == nil {
+		amount = new(big.Int)
+	}
+	tx := &Transaction{
+		Version:  NewVersion(uint16(b.chainID), DefaultMsgVersion).String(),
+		Amount:   amount.String(),
+		GasPrice: b.gasPrice.String(),
+		GasLimit: strconv.FormatUint(b.gasLimit, 10),
+		ToAddr:   "0000000000000000000000000000000000000000",
+		Data:     "",
+		Priority: b.priority,
+	}
+	if b.nonce > 0 {
+		tx.Nonce = strconv.FormatUint(b.nonce, 10)
+	}
+	if b.kind != deployKind {
+		address, _ := ParseAddress(b.toAddr)
+		tx.ToAddr = address.String()[2:]
+	}
+
+	switch b.kind {
+	case deployKind:
+		tx.Code = b.code
+		tx.Data = b.init
+	case callKind:
+		params := b.params
+		if params == nil {
+			params = []provider.Value{}
+		}
+		tx.Data = provider.Data{Tag: b.tag, Params: params}
+	}
+	return tx, nil
+}
+
+func (b *Builder) validate() error {
+	if b.chainID <= 0 || b.chainID > 0xffff {
+		return fmt.Errorf("chain ID %d invalid, set it with ForChain", b.chainID)
+	}
+	if b.gasPrice == nil || b.gasPrice.Sign() <= 0 {
+		return errors.New("gas price missing, set it with WithGasPrice")
+	}
+	if b.gasLimit == 0 {
+		return errors.New("gas limit missing, set it with WithGasLimit")
+	}
+	if b.gasPrice.BitLen() > amountBits {
+		return fmt.Errorf("gas price %s over %d bits", b.gasPrice, amountBits)
+	}
+	if b.amount != nil && b.amount.Sign() < 0 {
+		return fmt.Errorf("amount %s negative", b.amount)
+	}
+	if b.amount != nil && b.amount.BitLen() > amountBits {
+		return fmt.Errorf("amount %s over %d bits", b.amount, amountBits)
+	}
+
+	if b.kind != deployKind {
+		if !validator.IsBech32(b.toAddr) && !validator.IsChecksumAddress(b.toAddr) && !validator.IsChecksumAddress("0x"+b.toAddr) {
+			return fmt.Errorf("address %q is neither a checksum nor a bech32 address", b.toAddr)
+		}
+		address, err := ParseAddress(b.toAddr)
+		if err != nil {
+			return err
+		}
+		if address.IsZero() {
+			return errors.New("zero address is for deployments only")
+		}
+	}
+
+	switch b.kind {
+	case deployKind:
+		if b.code == "" {
+			return errors.New("contract code missing")
+		}
+		if len(b.init) == 0 {
+			return errors.New("contract init parameters missing")
+		}
+		if !hasScillaVersion(b.init) {
+			return errors.New("contract init parameters miss _scilla_version")
+		}
+	case callKind:
+		if b.tag == "" {
+			return errors.New("transition tag missing")
+		}
+	}
+	return nil
+}
+
+func hasScillaVersion(init []provider.Value) bool {
+	for _, value := range init {
+		if value.VName == "_scilla_version" {
+			return true
+		}
+	}
+	return false
+}
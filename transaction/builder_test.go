@@ -0,0 +1,113 @@
+/*
+ * Copyright (C) 2019 Zilliqa
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+package transaction_test
+
+import (
+	"github.com/Zilliqa/gozilliqa-sdk/account"
+	"github.com/Zilliqa/gozilliqa-sdk/keytools"
+	"github.com/Zilliqa/gozilliqa-sdk/provider"
+	"github.com/Zilliqa/gozilliqa-sdk/transaction"
+	"github.com/Zilliqa/gozilliqa-sdk/util"
+	"github.com/Zilliqa/gozilliqa-sdk/zilliqatest"
+	"github.com/stretchr/testify/assert"
+	"math/big"
+	"testing"
+)
+
+var scillaVersion = provider.Value{VName: "_scilla_version", Type: "Uint32", Value: "0"}
+
+func TestBuilder_Transfer(t *testing.T) {
+	server := zilliqatest.NewServer()
+	defer server.Close()
+	privateKey, err := keytools.GeneratePrivateKey()
+	assert.Nil(t, err, err)
+	server.Fund(keytools.GetAddressFromPrivateKey(privateKey[:]), "1000000000000")
+	p := server.Provider()
+
+	tx, err := transaction.Transfer("zil1fwh4ltdguhde9s7nysnp33d5wye6uqpugufkz7", big.NewInt(1000)).
+		ForChain(server.ChainID()).
+		WithGasPrice(big.NewInt(1000000000)).
+		Build()
+	assert.Nil(t, err, err)
+	assert.Equal(t, "4BAF5faDA8e5Db92C3d3242618c5B47133AE003C", tx.ToAddr)
+	assert.Equal(t, "50", tx.GasLimit)
+	assert.Equal(t, "", tx.Nonce)
+
+	wallet := account.NewWallet()
+	wallet.AddByPrivateKey(util.EncodeHex(privateKey[:]))
+	assert.Nil(t, wallet.Sign(tx, p))
+	assert.Equal(t, "1", tx.Nonce)
+	payload, err := tx.Payload()
+	assert.Nil(t, err, err)
+	created, err := p.CreateTransactionTyped(payload)
+	assert.Nil(t, err, err)
+	server.Mine()
+	result, err := p.GetTransactionTyped(created.TranID)
+	assert.Nil(t, err, err)
+	assert.True(t, result.Receipt.Success)
+}
+
+func TestBuilder_DeployAndCall(t *testing.T) {
+	tx, err := transaction.Deploy("scilla_version 0", []provider.Value{scillaVersion}).
+		ForChain(333).
+		WithGasPrice(big.NewInt(2000000000)).
+		WithGasLimit(10000).
+		WithNonce(7).
+		Build()
+	assert.Nil(t, err, err)
+	assert.Equal(t, "21823489", tx.Version)
+	assert.Equal(t, "7", tx.Nonce)
+	assert.Equal(t, "0", tx.Amount)
+	assert.Equal(t, "0000000000000000000000000000000000000000", tx.ToAddr)
+	assert.Equal(t, []provider.Value{scillaVersion}, tx.Data)
+
+	tx, err = transaction.Call("0x4BAF5faDA8e5Db92C3d3242618c5B47133AE003C", "Transfer", nil).
+		ForChain(1).
+		WithGasPrice(big.NewInt(2000000000)).
+		WithGasLimit(1000).
+		WithAmount(big.NewInt(5)).
+		WithPriority(true).
+		Build()
+	assert.Nil(t, err, err)
+	assert.Equal(t, provider.Data{Tag: "Transfer", Params: []provider.Value{}}, tx.Data)
+	assert.Equal(t, "5", tx.Amount)
+	assert.True(t, tx.Priority)
+	_, err = tx.Typed()
+	assert.Nil(t, err, err)
+}
+
+func TestBuilder_Validate(t *testing.T) {
+	to := "4BAF5faDA8e5Db92C3d3242618c5B47133AE003C"
+	gasPrice := big.NewInt(2000000000)
+	for name, builder := range map[string]*transaction.Builder{
+		"chain":           transaction.Transfer(to, big.NewInt(1)).WithGasPrice(gasPrice),
+		"gas price":       transaction.Transfer(to, big.NewInt(1)).ForChain(1),
+		"negative amount": transaction.Transfer(to, big.NewInt(-1)).ForChain(1).WithGasPrice(gasPrice),
+		"amount bits":     transaction.Transfer(to, new(big.Int).Lsh(big.NewInt(1), 128)).ForChain(1).WithGasPrice(gasPrice),
+		"gas price bits":  transaction.Transfer(to, big.NewInt(1)).ForChain(1).WithGasPrice(new(big.Int).Lsh(big.NewInt(1), 128)),
+		"not checksummed": transaction.Transfer("4baf5fada8e5db92c3d3242618c5b47133ae003c", big.NewInt(1)).ForChain(1).WithGasPrice(gasPrice),
+		"zero address":    transaction.Transfer("0x0000000000000000000000000000000000000000", big.NewInt(1)).ForChain(1).WithGasPrice(gasPrice),
+		"gas limit":       transaction.Call(to, "Transfer", nil).ForChain(1).WithGasPrice(gasPrice),
+		"tag":             transaction.Call(to, "", nil).ForChain(1).WithGasPrice(gasPrice).WithGasLimit(1000),
+		"code":            transaction.Deploy("", []provider.Value{scillaVersion}).ForChain(1).WithGasPrice(gasPrice).WithGasLimit(1000),
+		"init":            transaction.Deploy("scilla_version 0", nil).ForChain(1).WithGasPrice(gasPrice).WithGasLimit(1000),
+		"scilla version":  transaction.Deploy("scilla_version 0", []provider.Value{{VName: "owner"}}).ForChain(1).WithGasPrice(gasPrice).WithGasLimit(1000),
+	} {
+		_, err := builder.Build()
+		assert.NotNil(t, err, name)
+	}
+}
@@ -0,0 +1,73 @@
+/*
+ * Copyright (C) 2019 Zilliqa
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+package transaction_test
+
+import (
+	"errors"
+	"github.com/Zilliqa/gozilliqa-sdk/provider"
+	"github.com/Zilliqa/gozilliqa-sdk/transaction"
+	"github.com/Zilliqa/gozilliqa-sdk/zilliqatest"
+	"github.com/stretchr/testify/assert"
+	"math/big"
+	"testing"
+)
+
+// TestParseTxFromRpc_Synthetic replays a cassette written by hand in the shape of mainnet
+// answers. Its ID and signature are made up, so the transaction is only parsed, not hashed.
+func TestParseTxFromRpc_Synthetic(t *testing.T) {
+	recorder, err := zilliqatest.NewRecorder("testdata/synthetic.json", zilliqatest.ModeReplay, zilliqatest.WithStrict())
+	assert.Nil(t, err, err)
+	p := provider.NewProviderWithOptions("https://api.zilliqa.com/", provider.WithRoundTripper(recorder))
+
+	rsp, err := p.GetTransaction("1b5b9ccb3e8d006a5230de9bda23ff91edc794d4f56410560830b418528e446c")
+	assert.Nil(t, err, err)
+	tx, err := transaction.ParseTxFromRpc(rsp)
+	assert.Nil(t, err, err)
+	assert.Equal(t, "42", tx.Nonce)
+	assert.True(t, tx.Receipt.Success)
+	assert.Equal(t, "1664279", tx.Receipt.EpochNum)
+	assert.Equal(t, 1, len(tx.Receipt.Transitions))
+	assert.Equal(t, "RecipientAcceptTransfer", tx.Receipt.Transitions[0].Msg.Tag)
+	assertTypedReceipt(t, &tx.Receipt)
+
+	tracked := &transaction.Transaction{}
+	assert.True(t, tracked.TrackTx("1b5b9ccb3e8d006a5230de9bda23ff91edc794d4f56410560830b418528e446c", p))
+	assert.Equal(t, 1, len(tracked.Receipt.EventLogs))
+	assertTypedReceipt(t, &tracked.Receipt)
+
+	rsp, err = p.GetTransaction("0000000000000000000000000000000000000000000000000000000000000000")
+	assert.Nil(t, err, err)
+	assert.True(t, errors.Is(provider.ResponseError(rsp, err), provider.ErrTxnNotFound), rsp.Error)
+	_, err = transaction.ParseTxFromRpc(rsp)
+	assert.True(t, errors.Is(err, provider.ErrTxnNotFound), err)
+}
+
+func assertTypedReceipt(t *testing.T, receipt *transaction.TransactionReceipt) {
+	event := receipt.Events[0]
+	assert.Equal(t, "TransferSuccess", event.EventName)
+	amount, ok := event.Param("amount")
+	assert.True(t, ok)
+	n, err := amount.AsBigInt()
+	assert.Nil(t, err, err)
+	assert.Equal(t, big.NewInt(2500000), n)
+
+	recipient, ok := receipt.Transitions[0].Msg.Param("recipient")
+	assert.True(t, ok)
+	address, err := recipient.AsString()
+	assert.Nil(t, err, err)
+	assert.Equal(t, "0x665d0698dbc8fb95afc25c3a4d9cf280d87a585b", address)
+}
@@ -0,0 +1,321 @@
+/*
+ * Copyright (C) 2019 Zilliqa
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+package transaction
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/Zilliqa/gozilliqa-sdk/bech32"
+	"github.com/Zilliqa/gozilliqa-sdk/keytools"
+	"github.com/Zilliqa/gozilliqa-sdk/protobuf"
+	"github.com/Zilliqa/gozilliqa-sdk/provider"
+	go_schnorr "github.com/Zilliqa/gozilliqa-sdk/schnorr"
+	"github.com/Zilliqa/gozilliqa-sdk/util"
+	"github.com/golang/protobuf/proto"
+	"math/big"
+	"strings"
+)
+
+// EnvelopeVersion is the version of the envelope format written by this package.
+const EnvelopeVersion = 1
+
+// envelopeMagic starts the binary form of an envelope, followed by the version byte.
+const envelopeMagic = "ZTX"
+
+// envelopePriority is the flag of the binary form of an envelope marking a priority transaction.
+const envelopePriority = 1
+
+// ErrEnvelopeTampered is returned for envelopes whose chain ID, nonce or summary do not match
+// the transaction they carry.
+var ErrEnvelopeTampered = errors.New("envelope does not match its transaction")
+
+// Envelope carries a transaction from the machine that builds it to the machine that signs it,
+// possibly an air-gapped one, and back to be broadcast:
+//
+//	env, err := transaction.NewEnvelope(tx)    // online, without keys
+//	data, err := json.Marshal(env)
+//	...
+//	env, err := transaction.ParseEnvelope(data) // offline
+//	err = wallet.SignEnvelope(env, chainID)
+//	...
+//	payload, err := env.Payload()               // online again
+//	rsp, err := provider.CreateTransaction(payload)
+//
+// The transaction is kept in its protobuf encoding, the bytes that are signed. ChainID, Nonce
+// and Summary are for the people handling the envelope; they are checked against the
+// transaction whenever it is taken out of the envelope. Priority is not covered by the
+// signature, so it is carried along as it is.
+type Envelope struct {
+	Version     int             `json:"version"`
+	ChainID     uint16          `json:"chainId"`
+	Nonce       uint64          `json:"nonce"`
+	Priority    bool            `json:"priority,omitempty"`
+	Summary     EnvelopeSummary `json:"summary"`
+	Transaction string          `json:"transaction"`
+	Signature   string          `json:"signature,omitempty"`
+}
+
+// EnvelopeSummary describes the transaction of an envelope. Addresses are in bech32 form and
+// amounts in ZIL.
+type EnvelopeSummary struct {
+	Kind     string `json:"kind"`
+	From     string `json:"from"`
+	To       string `json:"to,omitempty"`
+	Tag      string `json:"tag,omitempty"`
+	Amount   string `json:"amount"`
+	GasPrice string `json:"gasPrice"`
+	GasLimit uint64 `json:"gasLimit"`
+	MaxFee   string `json:"maxFee"`
+	Hash     string `json:"hash"`
+}
+
+// NewEnvelope puts the unsigned transaction tx into an envelope. Its nonce and sender public key
+// must be set, so that the transaction signed offline is the one summarized here.
+func NewEnvelope(tx *Transaction) (*Envelope, error) {
+	if tx.Nonce == "" {
+		return nil, errors.New("new envelope: nonce missing")
+	}
+	if tx.SenderPubKey == "" {
+		return nil, errors.New("new envelope: sender public key missing")
+	}
+	typed, err := tx.Typed()
+	if err != nil {
+		return nil, fmt.Errorf("new envelope: %w", err)
+	}
+	core, err := typed.Bytes()
+	if err != nil {
+		return nil, fmt.Errorf("new envelope: %w", err)
+	}
+	summary, err := summarize(typed, core)
+	if err != nil {
+		return nil, fmt.Errorf("new envelope: %w", err)
+	}
+	return &Envelope{
+		Version:     EnvelopeVersion,
+		ChainID:     typed.Version.ChainID,
+		Nonce:       typed.Nonce,
+		Priority:    typed.Priority,
+		Summary:     summary,
+		Transaction: hex.EncodeToString(core),
+		Signature:   hex.EncodeToString(typed.Signature),
+	}, nil
+}
+
+// ParseEnvelope reads an envelope in its JSON or its binary form.
+func ParseEnvelope(data []byte) (*Envelope, error) {
+	env := &Envelope{}
+	var err error
+	if bytes.HasPrefix(data, []byte(envelopeMagic)) {
+		err = env.UnmarshalBinary(data)
+	} else {
+		err = json.Unmarshal(data, env)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if env.Version != EnvelopeVersion {
+		return nil, fmt.Errorf("parse envelope: version %d not supported", env.Version)
+	}
+	if _, err := env.Tx(); err != nil {
+		return nil, err
+	}
+	return env, nil
+}
+
+// Tx takes the transaction out of the envelope, with its signature if it is signed. It returns
+// ErrEnvelopeTampered if the chain ID, the nonce or the summary do not match it.
+func (env *Envelope) Tx() (*Transaction, error) {
+	core, err := hex.DecodeString(env.Transaction)
+	if err != nil {
+		return nil, fmt.Errorf("envelope transaction: %w", err)
+	}
+	tx, err := DecodeTransactionProto(core)
+	if err != nil {
+		return nil, err
+	}
+	tx.Signature = env.Signature
+	tx.Priority = env.Priority
+	typed, err := tx.Typed()
+	if err != nil {
+		return nil, err
+	}
+	summary, err := summarize(typed, core)
+	if err != nil {
+		return nil, err
+	}
+	if typed.Version.ChainID != env.ChainID || typed.Nonce != env.Nonce || summary != env.Summary {
+		return nil, ErrEnvelopeTampered
+	}
+	tx.ID = summary.Hash
+	return tx, nil
+}
+
+// Signed reports whether the envelope carries a signature.
+func (env *Envelope) Signed() bool {
+	return env.Signature != ""
+}
+
+// Sign puts the signature of the transaction, made by the key of Summary.From, into the
+// envelope. It fails if the signature does not verify.
+func (env *Envelope) Sign(signature string) error {
+	tx, err := env.Tx()
+	if err != nil {
+		return err
+	}
+	tx.Signature = signature
+	if err := verify(tx); err != nil {
+		return err
+	}
+	env.Signature = strings.ToLower(signature)
+	return nil
+}
+
+// Payload returns the signed transaction in the form CreateTransaction sends it.
+func (env *Envelope) Payload() (provider.TransactionPayload, error) {
+	if !env.Signed() {
+		return provider.TransactionPayload{}, errors.New("envelope not signed")
+	}
+	tx, err := env.Tx()
+	if err != nil {
+		return provider.TransactionPayload{}, err
+	}
+	if err := verify(tx); err != nil {
+		return provider.TransactionPayload{}, err
+	}
+	return tx.Payload()
+}
+
+// MarshalBinary returns the binary form of the envelope: "ZTX", the version byte, a flags byte
+// with envelopePriority and the protobuf encoding of protobuf.ProtoTransaction. ChainID, Nonce
+// and Summary are not written, they are derived from the transaction when reading it.
+func (env *Envelope) MarshalBinary() ([]byte, error) {
+	core, err := hex.DecodeString(env.Transaction)
+	if err != nil {
+		return nil, fmt.Errorf("envelope transaction: %w", err)
+	}
+	var info protobuf.ProtoTransactionCoreInfo
+	if err := proto.Unmarshal(core, &info); err != nil {
+		return nil, fmt.Errorf("envelope transaction: %w", err)
+	}
+	protoTx := &protobuf.ProtoTransaction{
+		Tranid: util.Sha256(core),
+		Info:   &info,
+	}
+	if env.Signed() {
+		signature, err := hex.DecodeString(env.Signature)
+		if err != nil {
+			return nil, fmt.Errorf("envelope signature: %w", err)
+		}
+		protoTx.Signature = &protobuf.ByteArray{Data: signature}
+	}
+	data, err := proto.Marshal(protoTx)
+	if err != nil {
+		return nil, err
+	}
+	var flags byte
+	if env.Priority {
+		flags |= envelopePriority
+	}
+	return append([]byte{envelopeMagic[0], envelopeMagic[1], envelopeMagic[2], EnvelopeVersion, flags}, data...), nil
+}
+
+// UnmarshalBinary reads the binary form written by MarshalBinary.
+func (env *Envelope) UnmarshalBinary(data []byte) error {
+	if len(data) < len(envelopeMagic)+2 || !bytes.HasPrefix(data, []byte(envelopeMagic)) {
+		return errors.New("parse envelope: not a binary envelope")
+	}
+	if version := int(data[len(envelopeMagic)]); version != EnvelopeVersion {
+		return fmt.Errorf("parse envelope: version %d not supported", version)
+	}
+	flags := data[len(envelopeMagic)+1]
+	if flags&^envelopePriority != 0 {
+		return fmt.Errorf("parse envelope: unknown flags %#x", flags)
+	}
+	tx, err := DecodeProtoTransaction(data[len(envelopeMagic)+2:])
+	if err != nil {
+		return err
+	}
+	signature := tx.Signature
+	tx.Signature = ""
+	tx.Priority = flags&envelopePriority != 0
+	decoded, err := NewEnvelope(tx)
+	if err != nil {
+		return err
+	}
+	if tx.ID != decoded.Summary.Hash {
+		return ErrEnvelopeTampered
+	}
+	decoded.Signature = signature
+	*env = *decoded
+	return nil
+}
+
+func summarize(typed *TypedTransaction, core []byte) (EnvelopeSummary, error) {
+	from, err := bech32.ToBech32Address(keytools.GetAddressFromPublic(typed.SenderPubKey))
+	if err != nil {
+		return EnvelopeSummary{}, fmt.Errorf("sender address: %w", err)
+	}
+	fee := new(big.Int).Mul(typed.gasPrice(), new(big.Int).SetUint64(typed.GasLimit))
+	summary := EnvelopeSummary{
+		Kind:     "transfer",
+		From:     from,
+		Amount:   formatZil(typed.amount()),
+		GasPrice: formatZil(typed.gasPrice()),
+		GasLimit: typed.GasLimit,
+		MaxFee:   formatZil(fee),
+		Hash:     util.EncodeHex(util.Sha256(core)),
+	}
+	if !typed.ToAddr.IsZero() {
+		if summary.To, err = bech32.ToBech32Address(typed.ToAddr.Hex()); err != nil {
+			return EnvelopeSummary{}, fmt.Errorf("recipient address: %w", err)
+		}
+	}
+	switch {
+	case typed.Code != "":
+		summary.Kind = "deploy"
+	case typed.Data != "":
+		var data struct {
+			Tag string `json:"_tag"`
+		}
+		if json.Unmarshal([]byte(typed.Data), &data) == nil && data.Tag != "" {
+			summary.Kind = "call"
+			summary.Tag = data.Tag
+		}
+	}
+	return summary, nil
+}
+
+// formatZil formats an amount in Qa as ZIL, e.g. 1500000000000 as "1.5".
+func formatZil(qa *big.Int) string {
+	zil := new(big.Rat).SetFrac(qa, big.NewInt(1000000000000)).FloatString(12)
+	return strings.TrimSuffix(strings.TrimRight(zil, "0"), ".")
+}
+
+func verify(tx *Transaction) error {
+	message, err := tx.Bytes()
+	if err != nil {
+		return err
+	}
+	signature := util.DecodeHex(tx.Signature)
+	if len(signature) != 64 || !go_schnorr.Verify(util.DecodeHex(tx.SenderPubKey), message, signature[:32], signature[32:]) {
+		return errors.New("signature does not verify")
+	}
+	return nil
+}
@@ -0,0 +1,136 @@
+/*
+ * Copyright (C) 2019 Zilliqa
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+package transaction
+
+import (
+	"encoding/json"
+	"github.com/Zilliqa/gozilliqa-sdk/provider"
+	"github.com/stretchr/testify/assert"
+	"math/big"
+	"testing"
+)
+
+func newTestEnvelope(t *testing.T) *Envelope {
+	tx, err := Call("0x4BAF5faDA8e5Db92C3d3242618c5B47133AE003C", "Transfer", []provider.Value{{VName: "amount", Type: "Uint128", Value: "1"}}).
+		ForChain(333).
+		WithGasPrice(big.NewInt(2000000000)).
+		WithGasLimit(1000).
+		WithNonce(3).
+		Build()
+	assert.Nil(t, err, err)
+	tx.SenderPubKey = "0246e7178dc8253201101e18fd6f6eb9972451d121fc57aa2a06dd5c111e58dc6a"
+	env, err := NewEnvelope(tx)
+	assert.Nil(t, err, err)
+	return env
+}
+
+func TestNewEnvelope(t *testing.T) {
+	env := newTestEnvelope(t)
+	assert.Equal(t, EnvelopeVersion, env.Version)
+	assert.Equal(t, uint16(333), env.ChainID)
+	assert.Equal(t, uint64(3), env.Nonce)
+	assert.False(t, env.Signed())
+	assert.Equal(t, "call", env.Summary.Kind)
+	assert.Equal(t, "Transfer", env.Summary.Tag)
+	assert.Equal(t, "zil1fwh4ltdguhde9s7nysnp33d5wye6uqpugufkz7", env.Summary.To)
+	assert.Equal(t, "0", env.Summary.Amount)
+	assert.Equal(t, "0.002", env.Summary.GasPrice)
+	assert.Equal(t, "2", env.Summary.MaxFee)
+
+	tx, err := env.Tx()
+	assert.Nil(t, err, err)
+	hash, err := tx.Hash()
+	assert.Nil(t, err, err)
+	assert.Equal(t, env.Summary.Hash, hash)
+
+	_, err = env.Payload()
+	assert.NotNil(t, err)
+	assert.NotNil(t, env.Sign("00"))
+
+	_, err = NewEnvelope(&Transaction{Version: "21823489", Amount: "0", GasPrice: "1", GasLimit: "1"})
+	assert.NotNil(t, err)
+}
+
+func TestParseEnvelope(t *testing.T) {
+	env := newTestEnvelope(t)
+	data, err := json.Marshal(env)
+	assert.Nil(t, err, err)
+	parsed, err := ParseEnvelope(data)
+	assert.Nil(t, err, err)
+	assert.Equal(t, env, parsed)
+
+	binary, err := env.MarshalBinary()
+	assert.Nil(t, err, err)
+	assert.Equal(t, "ZTX\x01", string(binary[:4]))
+	parsed, err = ParseEnvelope(binary)
+	assert.Nil(t, err, err)
+	assert.Equal(t, env, parsed)
+
+	// the summary shown to the signer must be the one of the transaction
+	tampered := *env
+	tampered.Summary.To = "zil1qqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqq"
+	data, err = json.Marshal(&tampered)
+	assert.Nil(t, err, err)
+	_, err = ParseEnvelope(data)
+	assert.Equal(t, ErrEnvelopeTampered, err)
+
+	tampered = *env
+	tampered.Nonce = 4
+	_, err = tampered.Tx()
+	assert.Equal(t, ErrEnvelopeTampered, err)
+
+	binary[3] = 2
+	_, err = ParseEnvelope(binary)
+	assert.NotNil(t, err)
+}
+
+func TestParseEnvelope_Priority(t *testing.T) {
+	tx, err := Transfer("0x4BAF5faDA8e5Db92C3d3242618c5B47133AE003C", big.NewInt(1)).
+		ForChain(333).
+		WithGasPrice(big.NewInt(2000000000)).
+		WithNonce(3).
+		WithPriority(true).
+		Build()
+	assert.Nil(t, err, err)
+	tx.SenderPubKey = "0246e7178dc8253201101e18fd6f6eb9972451d121fc57aa2a06dd5c111e58dc6a"
+	env, err := NewEnvelope(tx)
+	assert.Nil(t, err, err)
+	assert.True(t, env.Priority)
+
+	data, err := json.Marshal(env)
+	assert.Nil(t, err, err)
+	parsed, err := ParseEnvelope(data)
+	assert.Nil(t, err, err)
+	assert.Equal(t, env, parsed)
+
+	binary, err := env.MarshalBinary()
+	assert.Nil(t, err, err)
+	parsed, err = ParseEnvelope(binary)
+	assert.Nil(t, err, err)
+	assert.Equal(t, env, parsed)
+	tx, err = parsed.Tx()
+	assert.Nil(t, err, err)
+	assert.True(t, tx.Priority)
+
+	hash, err := tx.Hash()
+	assert.Nil(t, err, err)
+	assert.Equal(t, env.Summary.Hash, hash)
+
+	binary[4] = 2
+	_, err = ParseEnvelope(binary)
+	assert.NotNil(t, err)
+}
@@ -0,0 +1,236 @@
+/*
+ * Copyright (C) 2019 Zilliqa
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+package transaction
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/Zilliqa/gozilliqa-sdk/provider"
+	"time"
+)
+
+// StatusCode is the status of a transaction as reported by GetTransactionStatus.
+type StatusCode int
+
+const (
+	StatusNotFound                  StatusCode = 0
+	StatusDispatched                StatusCode = 1
+	StatusSoftConfirmed             StatusCode = 2
+	StatusConfirmed                 StatusCode = 3
+	StatusNonceTooHigh              StatusCode = 4
+	StatusGasLimitExceeded          StatusCode = 5
+	StatusConsensusFailure          StatusCode = 6
+	StatusMathError                 StatusCode = 10
+	StatusScillaInvocationError     StatusCode = 11
+	StatusContractInitError         StatusCode = 12
+	StatusInvalidSourceAccount      StatusCode = 13
+	StatusGasLimitAboveShardLimit   StatusCode = 14
+	StatusUnknownTxnType            StatusCode = 15
+	StatusWrongShard                StatusCode = 16
+	StatusCrossShardContract        StatusCode = 17
+	StatusCodeSizeExceeded          StatusCode = 18
+	StatusVerificationFailed        StatusCode = 19
+	StatusGasLimitTooLow            StatusCode = 20
+	StatusInsufficientBalance       StatusCode = 21
+	StatusInsufficientGasForChecker StatusCode = 22
+	StatusDuplicate                 StatusCode = 23
+	StatusSameNonceHigherGasPrice   StatusCode = 24
+	StatusInvalidToAddress          StatusCode = 25
+	StatusAddContractFailed         StatusCode = 26
+	StatusNonceTooLow               StatusCode = 27
+	StatusInternalError             StatusCode = 255
+)
+
+var statusDescriptions = map[StatusCode]string{
+	StatusNotFound:                  "transaction not found",
+	StatusDispatched:                "dispatched",
+	StatusSoftConfirmed:             "soft-confirmed, awaiting the Tx block",
+	StatusConfirmed:                 "confirmed",
+	StatusNonceTooHigh:              "nonce is higher than expected",
+	StatusGasLimitExceeded:          "microblock gas limit exceeded",
+	StatusConsensusFailure:          "consensus failure in network",
+	StatusMathError:                 "transaction caused math error",
+	StatusScillaInvocationError:     "Scilla invocation error",
+	StatusContractInitError:         "contract account initialization error",
+	StatusInvalidSourceAccount:      "invalid source account",
+	StatusGasLimitAboveShardLimit:   "contract call gas limit is higher than shard gas limit",
+	StatusUnknownTxnType:            "unknown transaction type",
+	StatusWrongShard:                "transaction sent to wrong shard",
+	StatusCrossShardContract:        "contract and source account cross-shard issue",
+	StatusCodeSizeExceeded:          "code size exceeded limit",
+	StatusVerificationFailed:        "transaction verification failed",
+	StatusGasLimitTooLow:            "gas limit too low",
+	StatusInsufficientBalance:       "insufficient balance",
+	StatusInsufficientGasForChecker: "insufficient gas to invoke Scilla checker",
+	StatusDuplicate:                 "duplicate transaction exists",
+	StatusSameNonceHigherGasPrice:   "transaction with same nonce but same or higher gas price exists",
+	StatusInvalidToAddress:          "invalid destination address",
+	StatusAddContractFailed:         "failed to add contract account to state",
+	StatusNonceTooLow:               "nonce is lower than expected",
+	StatusInternalError:             "internal error",
+}
+
+// String describes the status, e.g. "nonce is higher than expected".
+func (c StatusCode) String() string {
+	if description, ok := statusDescriptions[c]; ok {
+		return description
+	}
+	return fmt.Sprintf("unknown status %d", int(c))
+}
+
+// State returns the state a transaction with the status is in. Transactions that are not found
+// have no state of their own; PollStatus keeps them in their state or marks them Dropped.
+func (c StatusCode) State() State {
+	switch {
+	case c == StatusDispatched:
+		return Dispatched
+	case c == StatusSoftConfirmed:
+		return SoftConfirmed
+	case c == StatusConfirmed:
+		return Confirmed
+	case c >= StatusNonceTooHigh && c <= StatusConsensusFailure:
+		return Pending
+	case c >= StatusMathError:
+		return Rejected
+	}
+	return Initialised
+}
+
+// StatusChange is passed to the callback of Watch when the state or the status code of a
+// transaction changes.
+type StatusChange struct {
+	Hash string
+	From State
+	To   State
+	Code StatusCode
+}
+
+// PollStatus asks the node once for the status of the transaction hash and updates Status and
+// StatusCode. It returns the change and whether anything changed.
+//
+// GetTransactionStatus is used if the node serves it, GetPendingTxn otherwise. A transaction
+// that the node knew of and does not know anymore is Dropped. Once Confirmed, the receipt is
+// fetched with TrackTx, which marks the transaction Rejected if it failed; as long as the
+// receipt cannot be read, Status and StatusCode are left as they are.
+func (t *Transaction) PollStatus(hash string, reader provider.StatusReader) (StatusChange, bool, error) {
+	change := StatusChange{Hash: hash, From: t.Status}
+	code, err := fetchStatus(hash, reader)
+	if err != nil {
+		change.To, change.Code = t.Status, t.StatusCode
+		return change, false, err
+	}
+
+	state := code.State()
+	switch code {
+	case StatusNotFound:
+		state = t.Status
+		if t.StatusCode != StatusNotFound {
+			state = Dropped
+		}
+	case StatusConfirmed:
+		// the state follows the receipt, so until TrackTx reads it nothing changes and
+		// the next poll tries again
+		if t.Status == Confirmed || t.Status == Rejected || t.TrackTx(hash, reader) {
+			state = t.Status
+		} else {
+			state, code = t.Status, t.StatusCode
+		}
+	}
+
+	changed := state != t.Status || code != t.StatusCode
+	t.Status, t.StatusCode = state, code
+	change.To, change.Code = state, code
+	return change, changed, nil
+}
+
+// fetchStatus returns the status code of GetTransactionStatus, or one derived from GetPendingTxn
+// if the node does not serve GetTransactionStatus.
+func fetchStatus(hash string, reader provider.StatusReader) (StatusCode, error) {
+	rsp, err := reader.GetTransactionStatus(hash)
+	err = provider.ResponseError(rsp, err)
+	switch {
+	case err == nil:
+		status, err := provider.ParseTransactionStatus(rsp)
+		if err != nil {
+			return StatusNotFound, err
+		}
+		return StatusCode(status.Status), nil
+	case errors.Is(err, provider.ErrTxnNotFound):
+		return StatusNotFound, nil
+	case !errors.Is(err, provider.ErrMethodNotFound):
+		return StatusNotFound, err
+	}
+
+	rsp, err = reader.GetPendingTxn(hash)
+	err = provider.ResponseError(rsp, err)
+	if errors.Is(err, provider.ErrTxnNotFound) {
+		return StatusNotFound, nil
+	} else if err != nil {
+		return StatusNotFound, err
+	}
+	pending, err := provider.ParsePendingTxn(rsp)
+	if err != nil {
+		return StatusNotFound, err
+	}
+	if pending.Confirmed {
+		return StatusConfirmed, nil
+	}
+	switch pending.Code {
+	case 1:
+		return StatusNonceTooHigh, nil
+	case 2:
+		return StatusGasLimitExceeded, nil
+	case 3:
+		return StatusConsensusFailure, nil
+	}
+	return StatusNotFound, nil
+}
+
+// Watch polls the status of the transaction hash every interval with PollStatus until it is
+// Confirmed, Rejected or Dropped, calling onChange, if not nil, on every change. If ctx is done
+// first, ctx.Err() is returned and the last known status is kept: a transaction that is still
+// Pending or Dispatched is slow, not dropped. Errors of single polls are retried.
+func (t *Transaction) Watch(ctx context.Context, hash string, interval time.Duration, reader provider.StatusReader, onChange func(StatusChange)) error {
+	logger := loggerOf(reader)
+	for {
+		change, changed, err := t.PollStatus(hash, reader)
+		if err != nil {
+			logger.Error("poll status", "hash", hash, "error", err)
+		} else if changed {
+			logger.Debug("status", "hash", hash, "state", change.To, "code", change.Code)
+			if onChange != nil {
+				onChange(change)
+			}
+		}
+		if t.isFinal() {
+			return nil
+		}
+
+		timer := time.NewTimer(interval)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
+
+func (t *Transaction) isFinal() bool {
+	return t.Status == Confirmed || t.Status == Rejected || t.Status == Dropped
+}
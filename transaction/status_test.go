@@ -0,0 +1,158 @@
+/*
+ * Copyright (C) 2019 Zilliqa
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+package transaction_test
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/Zilliqa/gozilliqa-sdk/provider"
+	"github.com/Zilliqa/gozilliqa-sdk/transaction"
+	"github.com/Zilliqa/gozilliqa-sdk/zilliqatest"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestStatusCode_State(t *testing.T) {
+	assert.Equal(t, transaction.Dispatched, transaction.StatusDispatched.State())
+	assert.Equal(t, transaction.SoftConfirmed, transaction.StatusSoftConfirmed.State())
+	assert.Equal(t, transaction.Pending, transaction.StatusNonceTooHigh.State())
+	assert.Equal(t, transaction.Pending, transaction.StatusGasLimitExceeded.State())
+	assert.Equal(t, transaction.Rejected, transaction.StatusInsufficientBalance.State())
+	assert.Equal(t, transaction.Rejected, transaction.StatusInternalError.State())
+	assert.Equal(t, "nonce is higher than expected", transaction.StatusNonceTooHigh.String())
+	assert.Equal(t, "unknown status 7", transaction.StatusCode(7).String())
+	assert.Equal(t, "soft-confirmed", transaction.SoftConfirmed.String())
+}
+
+func TestTransaction_Watch(t *testing.T) {
+	server := zilliqatest.NewServer()
+	defer server.Close()
+	p := server.Provider()
+	hash, err := server.SendTransfer("4BAF5faDA8e5Db92C3d3242618c5B47133AE003C", "1000")
+	assert.Nil(t, err, err)
+	tx := &transaction.Transaction{}
+
+	var changes []transaction.StatusChange
+	onChange := func(change transaction.StatusChange) {
+		changes = append(changes, change)
+	}
+
+	// a transaction that is not mined yet is slow, not dropped
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	err = tx.Watch(ctx, hash, 10*time.Millisecond, p, onChange)
+	assert.Equal(t, context.DeadlineExceeded, err)
+	assert.Equal(t, transaction.Dispatched, tx.Status)
+	assert.Equal(t, 1, len(changes))
+
+	server.Mine()
+	err = tx.Watch(context.Background(), hash, 10*time.Millisecond, p, onChange)
+	assert.Nil(t, err, err)
+	assert.Equal(t, transaction.Confirmed, tx.Status)
+	assert.True(t, tx.Receipt.Success)
+	assert.Equal(t, []transaction.StatusChange{
+		{Hash: hash, From: transaction.Initialised, To: transaction.Dispatched, Code: transaction.StatusDispatched},
+		{Hash: hash, From: transaction.Dispatched, To: transaction.Confirmed, Code: transaction.StatusConfirmed},
+	}, changes)
+}
+
+// TestTransaction_PollStatusPendingTxn polls a node without GetTransactionStatus, which knows of
+// the transaction at first and then not anymore.
+func TestTransaction_PollStatusPendingTxn(t *testing.T) {
+	polls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var request struct {
+			ID     interface{}
+			Method string
+		}
+		_ = json.NewDecoder(r.Body).Decode(&request)
+		response := map[string]interface{}{"jsonrpc": "2.0", "id": request.ID}
+		switch {
+		case request.Method == "GetTransactionStatus":
+			response["error"] = map[string]interface{}{"code": -32601, "message": "METHOD_NOT_FOUND: The method being requested is not available on this server"}
+		case polls == 0:
+			polls++
+			response["result"] = map[string]interface{}{"code": 1, "confirmed": false, "info": "Nonce too high"}
+		default:
+			response["error"] = map[string]interface{}{"code": -20, "message": "Txn Hash not Present"}
+		}
+		_ = json.NewEncoder(w).Encode(response)
+	}))
+	defer server.Close()
+	p := provider.NewProvider(server.URL)
+
+	tx := &transaction.Transaction{}
+	change, changed, err := tx.PollStatus("abc", p)
+	assert.Nil(t, err, err)
+	assert.True(t, changed)
+	assert.Equal(t, transaction.Pending, change.To)
+	assert.Equal(t, transaction.StatusNonceTooHigh, change.Code)
+
+	change, changed, err = tx.PollStatus("abc", p)
+	assert.Nil(t, err, err)
+	assert.True(t, changed)
+	assert.Equal(t, transaction.Pending, change.From)
+	assert.Equal(t, transaction.Dropped, change.To)
+	assert.Equal(t, transaction.Dropped, tx.Status)
+}
+
+func TestTransaction_PollStatusReceiptMissing(t *testing.T) {
+	reads := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var request struct {
+			ID     interface{}
+			Method string
+		}
+		_ = json.NewDecoder(r.Body).Decode(&request)
+		response := map[string]interface{}{"jsonrpc": "2.0", "id": request.ID}
+		switch {
+		case request.Method == "GetTransactionStatus":
+			response["result"] = map[string]interface{}{"ID": "abc", "status": 3}
+		case reads == 0:
+			// the receipt of a just confirmed transaction may not be served yet
+			reads++
+			response["error"] = map[string]interface{}{"code": -20, "message": "Txn Hash not Present"}
+		default:
+			response["result"] = map[string]interface{}{
+				"ID":      "abc",
+				"receipt": map[string]interface{}{"cumulative_gas": "1", "epoch_num": "7", "success": true},
+			}
+		}
+		_ = json.NewEncoder(w).Encode(response)
+	}))
+	defer server.Close()
+	p := provider.NewProvider(server.URL)
+
+	tx := &transaction.Transaction{Status: transaction.Dispatched, StatusCode: transaction.StatusDispatched}
+	change, changed, err := tx.PollStatus("abc", p)
+	assert.Nil(t, err, err)
+	assert.False(t, changed)
+	assert.Equal(t, transaction.Dispatched, change.To)
+	assert.Equal(t, transaction.Dispatched, tx.Status)
+	assert.Equal(t, transaction.StatusDispatched, tx.StatusCode)
+
+	change, changed, err = tx.PollStatus("abc", p)
+	assert.Nil(t, err, err)
+	assert.True(t, changed)
+	assert.Equal(t, transaction.Dispatched, change.From)
+	assert.Equal(t, transaction.Confirmed, change.To)
+	assert.Equal(t, transaction.StatusConfirmed, tx.StatusCode)
+	assert.Equal(t, "7", tx.Receipt.EpochNum)
+}
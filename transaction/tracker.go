@@ -0,0 +1,335 @@
+/*
+ * Copyright (C) 2019 Zilliqa
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+package transaction
+
+import (
+	"context"
+	"errors"
+	"github.com/Zilliqa/gozilliqa-sdk/logging"
+	"github.com/Zilliqa/gozilliqa-sdk/provider"
+	"github.com/ybbus/jsonrpc"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// ErrTrackTimeout is the error of a TrackResult for a transaction that did not reach the
+// confirmation depth within its timeout.
+var ErrTrackTimeout = errors.New("transaction not confirmed in time")
+
+// TrackResult is the outcome of tracking one transaction with a Tracker. Status is Confirmed or
+// Rejected once the transaction has Depth confirmations; Err is set instead if it timed out.
+type TrackResult struct {
+	Hash          string
+	Status        State
+	Receipt       TransactionReceipt
+	BlockNum      uint64
+	Confirmations uint64
+	Err           error
+}
+
+// Tracker tracks the confirmation of many transactions at once. Instead of polling every
+// transaction in its own goroutine, all pending transactions are checked together, with batch
+// requests if the reader is a *provider.Provider, each time a new Tx block is seen.
+//
+//	tracker := transaction.NewTracker(p, transaction.WithConfirmations(3))
+//	go tracker.Run(ctx)
+//	tracker.Track(hash, nil)
+//	result := <-tracker.Results()
+type Tracker struct {
+	reader   provider.Reader
+	depth    uint64
+	timeout  time.Duration
+	interval time.Duration
+	blocks   <-chan uint64
+	logger   logging.Logger
+	results  chan *TrackResult
+
+	mu      sync.Mutex
+	pending map[string]*trackedTx
+}
+
+type trackedTx struct {
+	result   TrackResult
+	included bool
+	deadline time.Time
+	onDone   func(*TrackResult)
+}
+
+// TrackerOption configures a Tracker created with NewTracker.
+type TrackerOption func(*Tracker)
+
+// WithConfirmations sets the number of Tx blocks, counting the one the transaction is in, that
+// must exist before a transaction is reported. The default is 1.
+func WithConfirmations(depth uint64) TrackerOption {
+	return func(tracker *Tracker) {
+		if depth > 0 {
+			tracker.depth = depth
+		}
+	}
+}
+
+// WithTrackTimeout sets the default time a transaction is tracked before it is reported with
+// ErrTrackTimeout. The default is 5 minutes; 0 tracks transactions until Run returns.
+func WithTrackTimeout(timeout time.Duration) TrackerOption {
+	return func(tracker *Tracker) {
+		tracker.timeout = timeout
+	}
+}
+
+// WithPollInterval sets how often the Tx block height is polled, or how often timeouts are
+// checked if the blocks are received with WithBlocks. The default is 10 seconds.
+func WithPollInterval(interval time.Duration) TrackerOption {
+	return func(tracker *Tracker) {
+		if interval > 0 {
+			tracker.interval = interval
+		}
+	}
+}
+
+// WithBlocks makes the Tracker check the pending transactions on every Tx block number received
+// from blocks instead of polling the height, e.g. with subscription.BlockNumbers.
+func WithBlocks(blocks <-chan uint64) TrackerOption {
+	return func(tracker *Tracker) {
+		tracker.blocks = blocks
+	}
+}
+
+// WithTrackerLogger sets the logger the errors met while tracking go to. By default it is the
+// logger of the reader, if it has one.
+func WithTrackerLogger(logger logging.Logger) TrackerOption {
+	return func(tracker *Tracker) {
+		tracker.logger = logger
+	}
+}
+
+func NewTracker(reader provider.Reader, opts ...TrackerOption) *Tracker {
+	tracker := &Tracker{
+		reader:   reader,
+		depth:    1,
+		timeout:  5 * time.Minute,
+		interval: 10 * time.Second,
+		logger:   loggerOf(reader),
+		results:  make(chan *TrackResult, 100),
+		pending:  make(map[string]*trackedTx),
+	}
+	for _, opt := range opts {
+		opt(tracker)
+	}
+	tracker.logger = logging.OrNop(tracker.logger)
+	return tracker
+}
+
+// Track adds the transaction hash with the default timeout. If onDone is nil its result is sent
+// to Results, otherwise onDone is called with it from the goroutine running Run.
+func (tracker *Tracker) Track(hash string, onDone func(*TrackResult)) {
+	tracker.TrackWithTimeout(hash, tracker.timeout, onDone)
+}
+
+// TrackWithTimeout adds the transaction hash like Track, with its own timeout.
+func (tracker *Tracker) TrackWithTimeout(hash string, timeout time.Duration, onDone func(*TrackResult)) {
+	tx := &trackedTx{result: TrackResult{Hash: hash, Status: Pending}, onDone: onDone}
+	if timeout > 0 {
+		tx.deadline = time.Now().Add(timeout)
+	}
+	tracker.mu.Lock()
+	tracker.pending[hash] = tx
+	tracker.mu.Unlock()
+}
+
+// Results delivers the results of the transactions tracked without a callback. Run blocks
+// once its buffer is full, so it has to be drained.
+func (tracker *Tracker) Results() <-chan *TrackResult {
+	return tracker.results
+}
+
+// Len returns the number of transactions that are still tracked.
+func (tracker *Tracker) Len() int {
+	tracker.mu.Lock()
+	defer tracker.mu.Unlock()
+	return len(tracker.pending)
+}
+
+// Run checks the tracked transactions on every new Tx block until ctx is done, then returns
+// ctx.Err(). Transactions still tracked at that point are not reported.
+func (tracker *Tracker) Run(ctx context.Context) error {
+	reader := bindContext(ctx, tracker.reader)
+	ticker := time.NewTicker(tracker.interval)
+	defer ticker.Stop()
+
+	var height uint64
+	seen := false
+	for {
+		if tracker.blocks == nil {
+			if latest, err := latestTxBlock(reader); err != nil {
+				tracker.logger.Error("get tx block height", "error", err)
+			} else if !seen || latest > height {
+				height, seen = latest, true
+				tracker.check(ctx, reader, height)
+			}
+		}
+		tracker.expire(ctx, time.Now())
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		case block, ok := <-tracker.blocks:
+			if !ok {
+				tracker.blocks = nil
+				continue
+			}
+			if !seen || block > height {
+				height, seen = block, true
+				tracker.check(ctx, reader, height)
+			}
+		}
+	}
+}
+
+func latestTxBlock(reader provider.Reader) (uint64, error) {
+	rsp, err := reader.GetNumTxBlocks()
+	if err != nil {
+		return 0, err
+	}
+	n, err := provider.ParseUint(rsp)
+	if err != nil || n == 0 {
+		return 0, err
+	}
+	return n - 1, nil
+}
+
+// check fetches the transactions that are not in a block yet and reports those that are deep
+// enough below height.
+func (tracker *Tracker) check(ctx context.Context, reader provider.Reader, height uint64) {
+	tracker.mu.Lock()
+	var hashes []string
+	for hash, tx := range tracker.pending {
+		if !tx.included {
+			hashes = append(hashes, hash)
+		}
+	}
+	tracker.mu.Unlock()
+
+	for hash, found := range tracker.fetch(reader, hashes) {
+		tracker.mu.Lock()
+		if tx, ok := tracker.pending[hash]; ok {
+			tx.included = true
+			tx.result.Receipt = found.Receipt
+			tx.result.Status = Confirmed
+			if !found.Receipt.Success {
+				tx.result.Status = Rejected
+			}
+			tx.result.BlockNum, _ = strconv.ParseUint(found.Receipt.EpochNum, 10, 64)
+		}
+		tracker.mu.Unlock()
+	}
+
+	var done []*trackedTx
+	tracker.mu.Lock()
+	for hash, tx := range tracker.pending {
+		if !tx.included || height < tx.result.BlockNum {
+			continue
+		}
+		tx.result.Confirmations = height - tx.result.BlockNum + 1
+		if tx.result.Confirmations >= tracker.depth {
+			delete(tracker.pending, hash)
+			done = append(done, tx)
+		}
+	}
+	tracker.mu.Unlock()
+	tracker.deliver(ctx, done)
+}
+
+// fetch returns the transactions of hashes that are in a block.
+func (tracker *Tracker) fetch(reader provider.Reader, hashes []string) map[string]*Transaction {
+	found := make(map[string]*Transaction)
+	if len(hashes) == 0 {
+		return found
+	}
+
+	batcher, ok := reader.(*provider.Provider)
+	if !ok {
+		for _, hash := range hashes {
+			rsp, err := reader.GetTransaction(hash)
+			if tx := tracker.parse(hash, rsp, err); tx != nil {
+				found[hash] = tx
+			}
+		}
+		return found
+	}
+
+	batch := batcher.NewBatch()
+	for _, hash := range hashes {
+		batch.Add("GetTransaction", nil, hash)
+	}
+	results, err := batch.Send()
+	if err != nil {
+		tracker.logger.Error("get transactions", "count", len(hashes), "error", err)
+	}
+	for _, result := range results {
+		hash := result.Params[0].(string)
+		if tx := tracker.parse(hash, result.Response, result.Err); tx != nil {
+			found[hash] = tx
+		}
+	}
+	return found
+}
+
+func (tracker *Tracker) parse(hash string, rsp *jsonrpc.RPCResponse, err error) *Transaction {
+	if err == nil && rsp != nil {
+		var tx *Transaction
+		tx, err = ParseTxFromRpc(rsp)
+		if err == nil && tx.Receipt.EpochNum != "" {
+			return tx
+		}
+	}
+	if err != nil && !isTxnNotFound(err) {
+		tracker.logger.Error("track error", "hash", hash, "error", err)
+	}
+	return nil
+}
+
+// expire reports the transactions whose deadline passed before now.
+func (tracker *Tracker) expire(ctx context.Context, now time.Time) {
+	var done []*trackedTx
+	tracker.mu.Lock()
+	for hash, tx := range tracker.pending {
+		if !tx.deadline.IsZero() && now.After(tx.deadline) {
+			tx.result.Err = ErrTrackTimeout
+			delete(tracker.pending, hash)
+			done = append(done, tx)
+		}
+	}
+	tracker.mu.Unlock()
+	tracker.deliver(ctx, done)
+}
+
+func (tracker *Tracker) deliver(ctx context.Context, done []*trackedTx) {
+	for _, tx := range done {
+		result := tx.result
+		if tx.onDone != nil {
+			tx.onDone(&result)
+			continue
+		}
+		select {
+		case tracker.results <- &result:
+		case <-ctx.Done():
+			return
+		}
+	}
+}
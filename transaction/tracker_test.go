@@ -0,0 +1,137 @@
+/*
+ * Copyright (C) 2019 Zilliqa
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+package transaction_test
+
+import (
+	"context"
+	"github.com/Zilliqa/gozilliqa-sdk/provider"
+	"github.com/Zilliqa/gozilliqa-sdk/transaction"
+	"github.com/Zilliqa/gozilliqa-sdk/zilliqatest"
+	"github.com/stretchr/testify/assert"
+	"github.com/ybbus/jsonrpc"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+)
+
+// chainReader serves the transactions of blocks, without going through the network.
+type chainReader struct {
+	provider.Reader
+	mu     sync.Mutex
+	height uint64
+	blocks map[string]uint64
+	failed map[string]bool
+}
+
+func (r *chainReader) GetNumTxBlocks() (*jsonrpc.RPCResponse, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return &jsonrpc.RPCResponse{Result: strconv.FormatUint(r.height+1, 10)}, nil
+}
+
+func (r *chainReader) GetTransaction(hash string) (*jsonrpc.RPCResponse, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	block, ok := r.blocks[hash]
+	if !ok || block > r.height {
+		return &jsonrpc.RPCResponse{Error: &jsonrpc.RPCError{Code: -20, Message: "Txn Hash not Present"}}, nil
+	}
+	return &jsonrpc.RPCResponse{Result: map[string]interface{}{
+		"ID": hash,
+		"receipt": map[string]interface{}{
+			"cumulative_gas": "1",
+			"epoch_num":      strconv.FormatUint(block, 10),
+			"success":        !r.failed[hash],
+		},
+	}}, nil
+}
+
+func (r *chainReader) mine() {
+	r.mu.Lock()
+	r.height++
+	r.mu.Unlock()
+}
+
+func TestTracker_Confirmations(t *testing.T) {
+	reader := &chainReader{height: 10, blocks: map[string]uint64{"a": 11, "b": 11, "c": 12}, failed: map[string]bool{"b": true}}
+	tracker := transaction.NewTracker(reader, transaction.WithConfirmations(2), transaction.WithPollInterval(5*time.Millisecond))
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go tracker.Run(ctx)
+
+	var mu sync.Mutex
+	var called *transaction.TrackResult
+	tracker.Track("a", nil)
+	tracker.Track("b", nil)
+	tracker.Track("c", func(result *transaction.TrackResult) {
+		mu.Lock()
+		called = result
+		mu.Unlock()
+	})
+	tracker.TrackWithTimeout("never", 20*time.Millisecond, nil)
+
+	result := <-tracker.Results()
+	assert.Equal(t, "never", result.Hash)
+	assert.Equal(t, transaction.ErrTrackTimeout, result.Err)
+
+	reader.mine()
+	reader.mine()
+	byHash := map[string]*transaction.TrackResult{}
+	for i := 0; i < 2; i++ {
+		result := <-tracker.Results()
+		byHash[result.Hash] = result
+	}
+	assert.Equal(t, transaction.Confirmed, byHash["a"].Status)
+	assert.Equal(t, uint64(11), byHash["a"].BlockNum)
+	assert.Equal(t, uint64(2), byHash["a"].Confirmations)
+	assert.Nil(t, byHash["a"].Err)
+	assert.Equal(t, transaction.Rejected, byHash["b"].Status)
+	assert.Equal(t, 1, tracker.Len())
+
+	reader.mine()
+	assert.Eventually(t, func() bool {
+		mu.Lock()
+		defer mu.Unlock()
+		return called != nil
+	}, time.Second, 5*time.Millisecond)
+	assert.Equal(t, "c", called.Hash)
+	assert.Equal(t, uint64(2), called.Confirmations)
+	assert.Equal(t, 0, tracker.Len())
+}
+
+func TestTracker_Blocks(t *testing.T) {
+	server := zilliqatest.NewServer()
+	defer server.Close()
+	p := server.Provider()
+	hash, err := server.SendTransfer("4BAF5faDA8e5Db92C3d3242618c5B47133AE003C", "1000")
+	assert.Nil(t, err, err)
+
+	blocks := make(chan uint64)
+	tracker := transaction.NewTracker(p, transaction.WithBlocks(blocks), transaction.WithPollInterval(5*time.Millisecond))
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go tracker.Run(ctx)
+	tracker.Track(hash, nil)
+
+	blocks <- server.Mine()
+	result := <-tracker.Results()
+	assert.Nil(t, result.Err, result.Err)
+	assert.Equal(t, transaction.Confirmed, result.Status)
+	assert.Equal(t, uint64(1), result.BlockNum)
+	assert.True(t, result.Receipt.Success)
+}
@@ -1,12 +1,15 @@
 package transaction
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"strconv"
 	"strings"
 	"time"
 
+	"github.com/Zilliqa/gozilliqa-sdk/logging"
 	"github.com/Zilliqa/gozilliqa-sdk/provider"
 	"github.com/Zilliqa/gozilliqa-sdk/util"
 	"github.com/ybbus/jsonrpc"
@@ -19,8 +22,22 @@ const (
 	Pending
 	Confirmed
 	Rejected
+	// Dispatched, SoftConfirmed and Dropped are only set by PollStatus and Watch, which know
+	// more about a transaction than whether it is in a block yet.
+	Dispatched
+	SoftConfirmed
+	Dropped
 )
 
+var stateNames = [...]string{"initialised", "pending", "confirmed", "rejected", "dispatched", "soft-confirmed", "dropped"}
+
+func (s State) String() string {
+	if s >= 0 && int(s) < len(stateNames) {
+		return stateNames[s]
+	}
+	return fmt.Sprintf("state(%d)", int(s))
+}
+
 type Transaction struct {
 	ID           string
 	Version      string
@@ -36,8 +53,13 @@ type Transaction struct {
 	Data         interface{}
 
 	Status          State
+	StatusCode      StatusCode
 	ContractAddress string
 	Priority        bool
+
+	// dataJSON is set when Data is a string that already holds the JSON of the data, as in a
+	// payload or a transaction returned by the node, so that it is not encoded again.
+	dataJSON bool
 }
 
 func NewFromPayload(payload *provider.TransactionPayload) *Transaction {
@@ -59,11 +81,23 @@ func NewFromPayload(payload *provider.TransactionPayload) *Transaction {
 		Status:          0,
 		ContractAddress: "",
 		Priority:        payload.Priority,
+		dataJSON:        true,
 	}
 }
 
-func (t *Transaction) toTransactionParam() TxParams {
-	data, _ := json.Marshal(t.Data)
+// encodedData returns the JSON of the data of the transaction, or "" if it has none.
+func (t *Transaction) encodedData() (string, error) {
+	if data, ok := t.Data.(string); ok && t.dataJSON {
+		return encodeData(json.RawMessage(data))
+	}
+	return encodeData(t.Data)
+}
+
+func (t *Transaction) toTransactionParam() (TxParams, error) {
+	data, err := t.encodedData()
+	if err != nil {
+		return TxParams{}, err
+	}
 	param := TxParams{
 		ID:           t.ID,
 		Version:      t.Version,
@@ -75,7 +109,7 @@ func (t *Transaction) toTransactionParam() TxParams {
 		Receipt:      t.Receipt,
 		SenderPubKey: t.SenderPubKey,
 		Code:         t.Code,
-		Data:         string(data),
+		Data:         data,
 	}
 
 	if t.ToAddr == "" {
@@ -83,13 +117,17 @@ func (t *Transaction) toTransactionParam() TxParams {
 	} else {
 		param.ToAddr = t.ToAddr
 	}
-	return param
+	return param, nil
 }
 
+// ToTransactionPayload returns the transaction in the form CreateTransaction sends it. Fields
+// that do not parse are left zero.
+//
+// Deprecated: use Payload, which returns the error instead.
 func (t *Transaction) ToTransactionPayload() provider.TransactionPayload {
-	version, _ := strconv.ParseInt(t.Version, 10, 32)
-	nonce, _ := strconv.ParseInt(t.Nonce, 10, 32)
-	data, _ := json.Marshal(t.Data)
+	version, _ := strconv.ParseUint(t.Version, 10, 32)
+	nonce, _ := strconv.ParseUint(t.Nonce, 10, 64)
+	data, _ := t.encodedData()
 
 	p := provider.TransactionPayload{
 		Version:   int(version),
@@ -100,75 +138,165 @@ func (t *Transaction) ToTransactionPayload() provider.TransactionPayload {
 		GasPrice:  t.GasPrice,
 		GasLimit:  t.GasLimit,
 		Code:      t.Code,
+		Data:      data,
 		Signature: strings.ToLower(t.Signature),
 		Priority:  t.Priority,
 	}
 
-	if string(data) != "\"\"" {
-		p.Data = string(data)
-	}
-
 	if p.ToAddr == "0000000000000000000000000000000000000000" {
 		p.ToAddr = "0x0000000000000000000000000000000000000000"
 	}
 	return p
 }
 
-func (t *Transaction) TrackTx(hash string, provider *provider.Provider) bool {
+func (t *Transaction) TrackTx(hash string, provider provider.Reader) bool {
 	response, err := provider.GetTransaction(hash)
 
-	if err != nil {
-		fmt.Println("Track error: " + err.Error())
+	if err := responseError(response, err); err != nil {
+		// a transaction not yet in a block is expected while tracking
+		if !isTxnNotFound(err) {
+			loggerOf(provider).Error("track error", "hash", hash, "error", err)
+		}
 		return false
 	}
 
-	if response == nil || response.Error != nil {
+	if response == nil {
 		return false
 	}
 
-	result := response.Result.(map[string]interface{})
-	t.ID = result["ID"].(string)
+	result, ok := response.Result.(map[string]interface{})
+	id, idOK := result["ID"].(string)
+	if !ok || !idOK {
+		loggerOf(provider).Error("track error", "hash", hash, "error", fmt.Errorf("unexpected transaction %v", response.Result))
+		return false
+	}
+	t.ID = id
 
 	receipt, ok := result["receipt"].(map[string]interface{})
 	if !ok {
 		return false
 	}
 
-	epochNum, ok := receipt["epoch_num"]
-	if ok {
-		t.Receipt.EpochNum = epochNum.(string)
+	// decoding the receipt fills in the typed event logs and transitions as well
+	data, err := json.Marshal(receipt)
+	if err != nil {
+		loggerOf(provider).Error("receipt error", "hash", hash, "error", err)
+		return false
 	}
-
-	t.Receipt.CumulativeGas = receipt["cumulative_gas"].(string)
-	t.Receipt.Success = receipt["success"].(bool)
-	if receipt["event_logs"] != nil {
-		t.Receipt.EventLogs = receipt["event_logs"].([]interface{})
+	if err := json.Unmarshal(data, &t.Receipt); err != nil {
+		loggerOf(provider).Error("receipt error", "hash", hash, "error", err)
+		return false
 	}
 
 	if !t.Receipt.Success {
 		t.Status = Rejected
+		loggerOf(provider).Info("rejected", "hash", hash, "error", t.Receipt.Err())
 	} else {
 		t.Status = Confirmed
 	}
 	return true
 }
 
-func (t *Transaction) Confirm(hash string, maxAttempts, interval int, provider *provider.Provider) {
+func isTxnNotFound(err error) bool {
+	return errors.Is(err, provider.ErrTxnNotFound)
+}
+
+func isMethodNotFound(err error) bool {
+	return errors.Is(err, provider.ErrMethodNotFound)
+}
+
+func responseError(rsp *jsonrpc.RPCResponse, err error) error {
+	return provider.ResponseError(rsp, err)
+}
+
+func (t *Transaction) Confirm(hash string, maxAttempts, interval int, provider provider.Reader) {
+	_ = t.ConfirmWithContext(context.Background(), hash, maxAttempts, interval, provider)
+}
+
+// ConfirmWithContext polls the network like Confirm, but stops waiting as soon as ctx is done.
+// In that case the status is left as Pending and ctx.Err() is returned.
+//
+// If provider is a provider.StatusReader the status is polled with PollStatus, and a transaction
+// that is still on its way after maxAttempts keeps its state instead of being marked Rejected.
+// A node that serves neither GetTransactionStatus nor GetPendingTxn is tracked with TrackTx, as
+// for other readers.
+func (t *Transaction) ConfirmWithContext(ctx context.Context, hash string, maxAttempts, interval int, provider provider.Reader) error {
 	t.Status = Pending
+	p := bindContext(ctx, provider)
+	statusReader, polled := asStatusReader(p)
+	logger := loggerOf(provider)
 	for i := 0; i < maxAttempts; i++ {
-		fmt.Println("track " + hash)
-		tracked := t.TrackTx(hash, provider)
-		time.Sleep(time.Duration(interval) * time.Second)
-		if tracked {
-			fmt.Println("confirmed! " + hash)
-			return
+		logger.Debug("track", "hash", hash, "attempt", i+1)
+		if polled {
+			_, _, err := t.PollStatus(hash, statusReader)
+			if isMethodNotFound(err) {
+				logger.Info("status not served, tracking the receipt", "hash", hash)
+				polled = false
+			} else if err != nil {
+				logger.Error("poll status", "hash", hash, "error", err)
+			} else if t.isFinal() {
+				logger.Info(t.Status.String(), "hash", hash)
+				return nil
+			}
+		}
+		if !polled && t.TrackTx(hash, p) {
+			logger.Info("confirmed", "hash", hash)
+			return nil
+		}
+		timer := time.NewTimer(time.Duration(interval) * time.Second)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		case <-timer.C:
 		}
 	}
-	t.Status = Rejected
+	if !polled {
+		t.Status = Rejected
+	}
+	return nil
+}
+
+// bindContext binds the calls of reader to ctx if reader is a *provider.Provider.
+func bindContext(ctx context.Context, reader provider.Reader) provider.Reader {
+	if p, ok := reader.(*provider.Provider); ok {
+		return p.WithContext(ctx)
+	}
+	return reader
+}
+
+// asStatusReader returns reader as a provider.StatusReader if it implements one.
+func asStatusReader(reader provider.Reader) (provider.StatusReader, bool) {
+	r, ok := reader.(provider.StatusReader)
+	return r, ok
+}
+
+// loggerOf returns the logger of reader if it has one, like *provider.Provider, or logging.Nop.
+func loggerOf(reader provider.Reader) logging.Logger {
+	if l, ok := reader.(interface{ Logger() logging.Logger }); ok {
+		return l.Logger()
+	}
+	return logging.Nop
+}
+
+// Payload returns the transaction in the form CreateTransaction sends it, or the error of the
+// first field that does not parse.
+func (t *Transaction) Payload() (provider.TransactionPayload, error) {
+	if t.Nonce == "" {
+		return provider.TransactionPayload{}, errors.New("nonce missing")
+	}
+	typed, err := t.Typed()
+	if err != nil {
+		return provider.TransactionPayload{}, err
+	}
+	return typed.Payload()
 }
 
 func (t *Transaction) Bytes() ([]byte, error) {
-	txParams := t.toTransactionParam()
+	txParams, err := t.toTransactionParam()
+	if err != nil {
+		return nil, err
+	}
 	bytes, err := EncodeTransactionProto(txParams)
 
 	if err != nil {
@@ -178,6 +306,18 @@ func (t *Transaction) Bytes() ([]byte, error) {
 	}
 }
 
+// Hash returns the ID the network gives the transaction: the hex SHA-256 of Bytes. The
+// transaction must be complete, with nonce and sender public key, but need not be signed.
+// Data set to JSON by hand must be a json.RawMessage, like for signing; the string Data of
+// NewFromPayload and ParseTxFromRpc is taken as JSON already.
+func (t *Transaction) Hash() (string, error) {
+	bytes, err := t.Bytes()
+	if err != nil {
+		return "", err
+	}
+	return util.EncodeHex(util.Sha256(bytes)), nil
+}
+
 func (t *Transaction) isPending() bool {
 	return t.Status == Pending
 }
@@ -196,8 +336,7 @@ func (t *Transaction) isRejected() bool {
 
 func ParseTxFromRpc(rpcResult *jsonrpc.RPCResponse) (*Transaction, error) {
 	if rpcResult.Error != nil {
-		return nil, fmt.Errorf("ParseTxFromRpc: resp code %d, msg %s", rpcResult.Error.Code,
-			rpcResult.Error.Message)
+		return nil, fmt.Errorf("ParseTxFromRpc: %w", provider.NewRPCError(rpcResult.Error))
 	}
 	jsonResult, err := json.Marshal(rpcResult.Result)
 	if err != nil {
@@ -207,5 +346,6 @@ func ParseTxFromRpc(rpcResult *jsonrpc.RPCResponse) (*Transaction, error) {
 	if err := json.Unmarshal(jsonResult, &result); err != nil {
 		return nil, fmt.Errorf("ParseTx: unmarshal result, %s", err)
 	}
+	result.dataJSON = true
 	return result, nil
 }
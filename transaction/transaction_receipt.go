@@ -16,6 +16,11 @@
  */
 package transaction
 
+import (
+	"encoding/json"
+	"github.com/Zilliqa/gozilliqa-sdk/provider"
+)
+
 type TransactionReceipt struct {
 	Accepted      bool             `json:"accepted"`
 	Success       bool             `json:"success"`
@@ -23,6 +28,37 @@ type TransactionReceipt struct {
 	EpochNum      string           `json:"epoch_num"`
 	EventLogs     []interface{}    `json:"event_logs"`
 	Transitions   []*InnerTransfer `json:"transitions"`
+	// Errors and Exceptions tell why a transaction was rejected; see Err.
+	Errors     map[int][]provider.ReceiptErrorCode `json:"errors,omitempty"`
+	Exceptions []provider.ReceiptException         `json:"exceptions,omitempty"`
+
+	// Events are the EventLogs decoded into typed values.
+	Events []*provider.EventLog `json:"-"`
+}
+
+// Err returns nil if the transaction succeeded and a *provider.ReceiptError describing why it
+// was rejected otherwise. It is only meaningful once the transaction is confirmed or rejected.
+func (r *TransactionReceipt) Err() error {
+	if r.Success {
+		return nil
+	}
+	return provider.NewReceiptError(r.Errors, r.Exceptions)
+}
+
+// UnmarshalJSON fills in Events besides the raw EventLogs. Events is left empty if the event
+// logs do not have the expected shape.
+func (r *TransactionReceipt) UnmarshalJSON(data []byte) error {
+	type raw TransactionReceipt
+	if err := json.Unmarshal(data, (*raw)(r)); err != nil {
+		return err
+	}
+	var typed struct {
+		EventLogs []*provider.EventLog `json:"event_logs"`
+	}
+	if err := json.Unmarshal(data, &typed); err == nil {
+		r.Events = typed.EventLogs
+	}
+	return nil
 }
 
 type InnerTransfer struct {
@@ -36,4 +72,32 @@ type Msg struct {
 	Recipient string        `json:"_recipient"`
 	Tag       string        `json:"_tag"`
 	Params    []interface{} `json:"params"`
+
+	// TypedParams are the Params decoded into typed values.
+	TypedParams []provider.Value `json:"-"`
+}
+
+// UnmarshalJSON fills in TypedParams besides the raw Params.
+func (m *Msg) UnmarshalJSON(data []byte) error {
+	type raw Msg
+	if err := json.Unmarshal(data, (*raw)(m)); err != nil {
+		return err
+	}
+	var typed struct {
+		Params []provider.Value `json:"params"`
+	}
+	if err := json.Unmarshal(data, &typed); err == nil {
+		m.TypedParams = typed.Params
+	}
+	return nil
+}
+
+// Param returns the parameter of the message named vname.
+func (m *Msg) Param(vname string) (*provider.Value, bool) {
+	for i := range m.TypedParams {
+		if m.TypedParams[i].VName == vname {
+			return &m.TypedParams[i], true
+		}
+	}
+	return nil, false
 }
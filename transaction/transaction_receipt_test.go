@@ -2,7 +2,10 @@ package transaction
 
 import (
 	"encoding/json"
+	"errors"
+	"github.com/Zilliqa/gozilliqa-sdk/provider"
 	"github.com/stretchr/testify/assert"
+	"math/big"
 	"testing"
 )
 
@@ -47,4 +50,41 @@ func TestTransactionReceipt(t *testing.T) {
 	err := json.Unmarshal(js, &receipt)
 	assert.Nil(t, err, err)
 	t.Log(receipt)
+
+	assert.Equal(t, 2, len(receipt.EventLogs))
+	assert.Equal(t, "unsorted", receipt.Events[1].EventName)
+	sorted, ok := receipt.Events[0].Param("sorted")
+	assert.True(t, ok)
+	decoded, err := sorted.Decode()
+	assert.Nil(t, err, err)
+	assert.Equal(t, []interface{}{big.NewInt(1), big.NewInt(2)}, decoded)
+}
+
+func TestTransactionReceipt_Transitions(t *testing.T) {
+	js := []byte(`{"success":true,"transitions":[{"addr":"0x297241320bfd1796efb1780e7a6732dfbe93220f","depth":1,
+		"msg":{"_amount":"0","_recipient":"0x9bfec715a6bd658fcb62b0f8cc9bfa2ade71434a","_tag":"Credit",
+		"params":[{"type":"Uint128","value":"100","vname":"amount"}]}}]}`)
+	var receipt TransactionReceipt
+	err := json.Unmarshal(js, &receipt)
+	assert.Nil(t, err, err)
+
+	msg := receipt.Transitions[0].Msg
+	assert.Equal(t, 1, len(msg.Params))
+	amount, ok := msg.Param("amount")
+	assert.True(t, ok)
+	n, err := amount.AsBigInt()
+	assert.Nil(t, err, err)
+	assert.Equal(t, big.NewInt(100), n)
+}
+
+func TestTransactionReceipt_Err(t *testing.T) {
+	js := []byte(`{"cumulative_gas":"1","epoch_num":"9","success":false,"errors":{"0":[21]},"exceptions":[]}`)
+	var receipt TransactionReceipt
+	err := json.Unmarshal(js, &receipt)
+	assert.Nil(t, err, err)
+	assert.True(t, errors.Is(receipt.Err(), provider.ErrTxnFailed))
+	assert.Contains(t, receipt.Err().Error(), "GAS_NOT_SUFFICIENT")
+
+	receipt.Success = true
+	assert.Nil(t, receipt.Err())
 }
@@ -17,10 +17,21 @@
 package transaction
 
 import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"github.com/Zilliqa/gozilliqa-sdk/logging"
 	"github.com/Zilliqa/gozilliqa-sdk/provider"
+	go_schnorr "github.com/Zilliqa/gozilliqa-sdk/schnorr"
+	"github.com/Zilliqa/gozilliqa-sdk/util"
 	"github.com/stretchr/testify/assert"
+	"github.com/ybbus/jsonrpc"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
 	"os"
 	"testing"
+	"time"
 )
 
 func TestTransaction_TrackTx(t *testing.T) {
@@ -52,6 +63,125 @@ func TestTransaction_TrackTx(t *testing.T) {
 	assert.True(t, tx.Status == Confirmed)
 }
 
+func TestTransaction_ConfirmWithContext(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"id":0,"jsonrpc":"2.0","error":{"code":-20,"message":"Txn Hash not Present"}}`))
+	}))
+	defer server.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+	defer cancel()
+	tx := Transaction{}
+	start := time.Now()
+	err := tx.ConfirmWithContext(ctx, "846cda64971e259b1739bf15710758803abcf5754507af5af3f779777cd1b0b0", 1000, 3, provider.NewProvider(server.URL))
+	assert.Equal(t, context.DeadlineExceeded, err)
+	assert.Equal(t, Pending, tx.Status)
+	assert.True(t, time.Since(start) < 3*time.Second)
+}
+
+// legacyNode serves GetTransaction only, like a node from before GetTransactionStatus and
+// GetPendingTxn. The transaction has a receipt if confirmed is set.
+func legacyNode(confirmed bool) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			Method string `json:"method"`
+		}
+		body, _ := ioutil.ReadAll(r.Body)
+		_ = json.Unmarshal(body, &req)
+		switch {
+		case req.Method != "GetTransaction":
+			_, _ = w.Write([]byte(`{"id":0,"jsonrpc":"2.0","error":{"code":-32601,"message":"METHOD_NOT_FOUND: The method being requested is not available on this server"}}`))
+		case confirmed:
+			_, _ = w.Write([]byte(`{"id":0,"jsonrpc":"2.0","result":{"ID":"846cda64971e259b1739bf15710758803abcf5754507af5af3f779777cd1b0b0","receipt":{"cumulative_gas":"1","epoch_num":"42","success":true}}}`))
+		default:
+			_, _ = w.Write([]byte(`{"id":0,"jsonrpc":"2.0","error":{"code":-20,"message":"Txn Hash not Present"}}`))
+		}
+	}))
+}
+
+func TestTransaction_ConfirmWithContextLegacyNode(t *testing.T) {
+	server := legacyNode(true)
+	defer server.Close()
+	tx := Transaction{}
+	assert.Nil(t, tx.ConfirmWithContext(context.Background(), "846cda64971e259b1739bf15710758803abcf5754507af5af3f779777cd1b0b0", 2, 0, provider.NewProvider(server.URL)))
+	assert.Equal(t, Confirmed, tx.Status)
+	assert.Equal(t, "42", tx.Receipt.EpochNum)
+
+	// without the status methods a transaction still unknown after maxAttempts is Rejected
+	pending := legacyNode(false)
+	defer pending.Close()
+	tx = Transaction{}
+	assert.Nil(t, tx.ConfirmWithContext(context.Background(), "846cda64971e259b1739bf15710758803abcf5754507af5af3f779777cd1b0b0", 2, 0, provider.NewProvider(pending.URL)))
+	assert.Equal(t, Rejected, tx.Status)
+}
+
+// receiptReader returns a confirmed transaction, without going through the network.
+type receiptReader struct {
+	provider.Reader
+}
+
+func (receiptReader) GetTransaction(transactionHash string) (*jsonrpc.RPCResponse, error) {
+	return &jsonrpc.RPCResponse{Result: map[string]interface{}{
+		"ID": transactionHash,
+		"receipt": map[string]interface{}{
+			"cumulative_gas": "1",
+			"epoch_num":      "42",
+			"success":        true,
+		},
+	}}, nil
+}
+
+func TestTransaction_TrackTxReader(t *testing.T) {
+	tx := Transaction{}
+	assert.Nil(t, tx.ConfirmWithContext(context.Background(), "846cda64971e259b1739bf15710758803abcf5754507af5af3f779777cd1b0b0", 1, 1, receiptReader{}))
+	assert.Equal(t, Confirmed, tx.Status)
+	assert.Equal(t, "42", tx.Receipt.EpochNum)
+}
+
+// badReceiptReader returns a receipt that does not decode, and logs to logger.
+type badReceiptReader struct {
+	provider.Reader
+	logger logging.Logger
+}
+
+func (r badReceiptReader) Logger() logging.Logger {
+	return r.logger
+}
+
+func (badReceiptReader) GetTransaction(transactionHash string) (*jsonrpc.RPCResponse, error) {
+	return &jsonrpc.RPCResponse{Result: map[string]interface{}{
+		"ID":      transactionHash,
+		"receipt": map[string]interface{}{"success": "yes"},
+	}}, nil
+}
+
+// resultReader returns result as the transaction.
+type resultReader struct {
+	provider.Reader
+	result interface{}
+}
+
+func (r resultReader) GetTransaction(transactionHash string) (*jsonrpc.RPCResponse, error) {
+	return &jsonrpc.RPCResponse{Result: r.result}, nil
+}
+
+func TestTransaction_TrackTxBadResult(t *testing.T) {
+	for _, result := range []interface{}{nil, "846cda64", map[string]interface{}{"ID": 42}} {
+		tx := Transaction{}
+		assert.False(t, tx.TrackTx("846cda64971e259b1739bf15710758803abcf5754507af5af3f779777cd1b0b0", resultReader{result: result}))
+		assert.Equal(t, Initialised, tx.Status)
+	}
+}
+
+func TestTransaction_TrackTxBadReceipt(t *testing.T) {
+	var buf bytes.Buffer
+	tx := Transaction{}
+	reader := badReceiptReader{logger: logging.NewTextLogger(&buf, logging.LevelError)}
+	assert.False(t, tx.TrackTx("846cda64971e259b1739bf15710758803abcf5754507af5af3f779777cd1b0b0", reader))
+	assert.Equal(t, Initialised, tx.Status)
+	assert.Contains(t, buf.String(), "receipt error")
+}
+
 func TestNewFromPayload(t *testing.T) {
 	data := []byte(`{
     "version": 65537,
@@ -137,8 +267,99 @@ func TestNewFromPayload2(t *testing.T) {
 		Status:       0,
 	}
 
-	payload := tx.ToTransactionPayload()
+	payload, err := tx.Payload()
+	assert.Nil(t, err, err)
 	data, err := payload.ToJson()
 	assert.Nil(t, err, err)
 	t.Log(string(data))
 }
+
+// TestTransaction_Hash hashes transactions signed for the network, taken from the provider
+// tests. Their signatures only verify if Bytes is the message the node hashes into the ID.
+func TestTransaction_Hash(t *testing.T) {
+	var payload provider.TransactionPayload
+	err := json.Unmarshal([]byte(`{"version":65537,"nonce":1,"toAddr":"39550aB45D74cCe5feF70e857c1326b2d9bEE096","pubKey":"03bb0637134af801bcc912f7cf61448aed05fea21f4a6460a7f15a48c8704f2aea","amount":"0","gasPrice":"1000000000","gasLimit":"40000","code":"","data":"{\"_tag\":\"ProxyTransfer\",\"params\":[{\"vname\":\"to\",\"type\":\"ByStr20\",\"value\":\"0x0200a288be83e2a2061d7519d3397b3c6da05f29\"},{\"vname\":\"value\",\"type\":\"Uint128\",\"value\":\"10000000\"}]}","signature":"edf8d36c24e1d9e8e6832aa8e513690afe6df2f076756b45286200dfa02e202525ad0a7349b9c00f10ceb8ce2b71e9cfc745a679e68a6b034d581383155213b0","priority":false}`), &payload)
+	assert.Nil(t, err, err)
+	tx := NewFromPayload(&payload)
+	assertSigned(t, tx)
+	_, err = tx.Hash()
+	assert.Nil(t, err, err)
+
+	// the same transaction as sent, with the data as an object, and as a node returns it, with
+	// the data as JSON in a string, has the same ID
+	sent, err := provider.NewFromJson([]byte(`{"version":21823489,"nonce":959,"toAddr":"84eb5C96Bec8d29eDdFBe36865E9B7F26b816f0F","amount":0,"pubKey":"0246e7178dc8253201101e18fd6f6eb9972451d121fc57aa2a06dd5c111e58dc6a","gasPrice":1000000000,"gasLimit":10000,"code":"","data":{"_tag":"SubmitCustomMintTransaction","params":[{"vname":"proxyTokenContract","type":"ByStr20","value":"0x39550ab45d74cce5fef70e857c1326b2d9bee096"},{"vname":"to","type":"ByStr20","value":"0x39550ab45d74cce5fef70e857c1326b2d9bee096"},{"vname":"value","type":"Uint128","value":"10000000"}]},"signature":"c0dcffb4f5ef80b9e426c16fc1fb62b31356219deb84c5689ab6a73915ea962c0bc4d4a49985803cd1db8aabb6870e8c749003cab41246e17493767acc6cca90"}`))
+	assert.Nil(t, err, err)
+	// NewFromJson leaves out the public key
+	sent.PubKey = "0246e7178dc8253201101e18fd6f6eb9972451d121fc57aa2a06dd5c111e58dc6a"
+	tx = NewFromPayload(sent)
+	assertSigned(t, tx)
+	hash, err := tx.Hash()
+	assert.Nil(t, err, err)
+	// the data is kept as the string of the payload
+	data, ok := tx.Data.(string)
+	assert.True(t, ok)
+	assert.Equal(t, sent.Data, data)
+
+	rsp := &jsonrpc.RPCResponse{Result: map[string]interface{}{
+		"ID":           "",
+		"version":      "21823489",
+		"nonce":        "959",
+		"amount":       "0",
+		"gasPrice":     "1000000000",
+		"gasLimit":     "10000",
+		"signature":    "0xc0dcffb4f5ef80b9e426c16fc1fb62b31356219deb84c5689ab6a73915ea962c0bc4d4a49985803cd1db8aabb6870e8c749003cab41246e17493767acc6cca90",
+		"senderPubKey": "0x0246E7178DC8253201101E18FD6F6EB9972451D121FC57AA2A06DD5C111E58DC6A",
+		"toAddr":       "84eb5c96bec8d29eddfbe36865e9b7f26b816f0f",
+		"data":         `{"_tag":"SubmitCustomMintTransaction","params":[{"vname":"proxyTokenContract","type":"ByStr20","value":"0x39550ab45d74cce5fef70e857c1326b2d9bee096"},{"vname":"to","type":"ByStr20","value":"0x39550ab45d74cce5fef70e857c1326b2d9bee096"},{"vname":"value","type":"Uint128","value":"10000000"}]}`,
+	}}
+	tx, err = ParseTxFromRpc(rsp)
+	assert.Nil(t, err, err)
+	assertSigned(t, tx)
+	returned, err := tx.Hash()
+	assert.Nil(t, err, err)
+	assert.Equal(t, hash, returned)
+	assert.Equal(t, data, tx.Data)
+	sentPayload, err := tx.Payload()
+	assert.Nil(t, err, err)
+	assert.Equal(t, data, sentPayload.Data)
+
+	_, err = (&Transaction{Version: "1", Amount: "x"}).Hash()
+	assert.NotNil(t, err)
+}
+
+// nodeTxHash is a transaction on the developer testnet, so its ID was computed by the node.
+const nodeTxHash = "846cda64971e259b1739bf15710758803abcf5754507af5af3f779777cd1b0b0"
+
+// nodeTransaction reads the transaction nodeTxHash from the developer testnet.
+func nodeTransaction(t *testing.T) *Transaction {
+	rsp, err := provider.NewProvider("https://dev-api.zilliqa.com/").GetTransaction(nodeTxHash)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tx, err := ParseTxFromRpc(rsp)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return tx
+}
+
+// TestTransaction_HashNode checks Hash against the ID the node gave the transaction, which the
+// tests above cannot: they only compare hashes computed by this package.
+func TestTransaction_HashNode(t *testing.T) {
+	if os.Getenv("CI") != "" {
+		t.Skip("Skipping testing in CI environment")
+	}
+	tx := nodeTransaction(t)
+	assert.Equal(t, nodeTxHash, tx.ID)
+	assertSigned(t, tx)
+	hash, err := tx.Hash()
+	assert.Nil(t, err, err)
+	assert.Equal(t, nodeTxHash, hash)
+}
+
+func assertSigned(t *testing.T, tx *Transaction) {
+	message, err := tx.Bytes()
+	assert.Nil(t, err, err)
+	signature := util.DecodeHex(tx.Signature)
+	assert.True(t, go_schnorr.Verify(util.DecodeHex(tx.SenderPubKey), message, signature[:32], signature[32:]))
+}
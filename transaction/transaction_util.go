@@ -21,83 +21,178 @@ import (
 	"errors"
 	"fmt"
 	"github.com/Zilliqa/gozilliqa-sdk/protobuf"
-	"github.com/Zilliqa/gozilliqa-sdk/util"
 	"github.com/golang/protobuf/proto"
 	"math/big"
-	"strconv"
 )
 
 func EncodeTransactionProto(txParams TxParams) ([]byte, error) {
-	amount, ok := new(big.Int).SetString(txParams.Amount, 10)
-	if !ok {
-		return nil, errors.New("amount error")
+	tx := &Transaction{
+		Version:      txParams.Version,
+		Nonce:        txParams.Nonce,
+		Amount:       txParams.Amount,
+		GasPrice:     txParams.GasPrice,
+		GasLimit:     txParams.GasLimit,
+		SenderPubKey: txParams.SenderPubKey,
+		ToAddr:       txParams.ToAddr,
+		Code:         txParams.Code,
+		Data:         "",
 	}
-
-	gasPrice, ok2 := new(big.Int).SetString(txParams.GasPrice, 10)
-	if !ok2 {
-		return nil, errors.New("gas price error")
+	if txParams.Nonce == "" {
+		return nil, errors.New("nonce missing")
 	}
-
-	v, err := strconv.ParseUint(txParams.Version, 10, 32)
+	typed, err := tx.Typed()
 	if err != nil {
 		return nil, err
 	}
-	version := uint32(v)
-
-	nonce, err2 := strconv.ParseUint(txParams.Nonce, 10, 64)
-	if err2 != nil {
-		return nil, err2
+	// the data of TxParams is already encoded
+	if txParams.Data != "\"\"" {
+		typed.Data = txParams.Data
 	}
+	return encodeTypedProto(typed)
+}
+
+func encodeTypedProto(typed *TypedTransaction) ([]byte, error) {
+	version := typed.Version.Uint32()
+	nonce := typed.Nonce
+	gasLimit := typed.GasLimit
 
 	senderpubkey := protobuf.ByteArray{
-		Data: util.DecodeHex(txParams.SenderPubKey),
+		Data: typed.SenderPubKey,
 	}
 
-	amountArray := protobuf.ByteArray{
-		Data: bigIntToPaddedBytes(amount, 32),
+	// amounts and gas prices are 128-bit big-endian integers
+	if typed.amount().BitLen() > amountBits {
+		return nil, fmt.Errorf("encode transaction: amount %s over %d bits", typed.amount(), amountBits)
+	}
+	if typed.gasPrice().BitLen() > amountBits {
+		return nil, fmt.Errorf("encode transaction: gas price %s over %d bits", typed.gasPrice(), amountBits)
 	}
 
-	gasPriceArray := protobuf.ByteArray{
-		Data: bigIntToPaddedBytes(gasPrice, 32),
+	amountArray := protobuf.ByteArray{
+		Data: bigIntToPaddedBytes(typed.amount(), 32),
 	}
 
-	gasLimit, err3 := strconv.ParseUint(txParams.GasLimit, 10, 64)
-	if err3 != nil {
-		return nil, err3
+	gasPriceArray := protobuf.ByteArray{
+		Data: bigIntToPaddedBytes(typed.gasPrice(), 32),
 	}
 
 	protoTransactionCoreInfo := protobuf.ProtoTransactionCoreInfo{
 		Version:      &version,
 		Nonce:        &nonce,
-		Toaddr:       util.DecodeHex(txParams.ToAddr),
+		Toaddr:       typed.ToAddr[:],
 		Senderpubkey: &senderpubkey,
 		Amount:       &amountArray,
 		Gasprice:     &gasPriceArray,
 		Gaslimit:     &gasLimit,
 	}
 
-	if txParams.Data == "\"\"" {
-		txParams.Data = ""
-	}
-
-	if txParams.Data != "" {
-		protoTransactionCoreInfo.Data = []byte(txParams.Data)
+	if typed.Data != "" {
+		protoTransactionCoreInfo.Data = []byte(typed.Data)
 	}
 
-	if txParams.Code != "" {
-		protoTransactionCoreInfo.Code = []byte(txParams.Code)
+	if typed.Code != "" {
+		protoTransactionCoreInfo.Code = []byte(typed.Code)
 	}
 
-	bytes, err4 := proto.Marshal(&protoTransactionCoreInfo)
-	if err4 != nil {
-		return nil, err4
+	bytes, err := proto.Marshal(&protoTransactionCoreInfo)
+	if err != nil {
+		return nil, err
 	}
 	return bytes, nil
 
 }
 
+// amountBits is the size of the amounts and gas prices of a transaction, which are encoded as
+// big-endian integers of 16 bytes.
+const amountBits = 128
+
 func bigIntToPaddedBytes(i *big.Int, paddedSize int32) []byte {
 	bytes := i.Bytes()
 	padded, _ := hex.DecodeString(fmt.Sprintf("%0*x", paddedSize, bytes))
 	return padded
 }
+
+// DecodeTransactionProto is the inverse of EncodeTransactionProto: it rebuilds the transaction
+// from the protobuf encoding of its core info, the bytes that are signed. The amount and gas
+// price are unpadded from their 16 byte big-endian form. Data is kept as the JSON it was
+// encoded from, so the transaction encodes to the same bytes again.
+func DecodeTransactionProto(bytes []byte) (*Transaction, error) {
+	var info protobuf.ProtoTransactionCoreInfo
+	if err := proto.Unmarshal(bytes, &info); err != nil {
+		return nil, fmt.Errorf("decode transaction: %w", err)
+	}
+	typed, err := decodeCoreInfo(&info)
+	if err != nil {
+		return nil, err
+	}
+	return typed.Transaction(), nil
+}
+
+// DecodeProtoTransaction rebuilds a transaction from the protobuf encoding of a whole
+// protobuf.ProtoTransaction, with the core info, the transaction ID and the signature. The ID is
+// taken as it is; Hash recomputes it from the core info.
+func DecodeProtoTransaction(bytes []byte) (*Transaction, error) {
+	var protoTx protobuf.ProtoTransaction
+	if err := proto.Unmarshal(bytes, &protoTx); err != nil {
+		return nil, fmt.Errorf("decode transaction: %w", err)
+	}
+	if protoTx.Info == nil {
+		return nil, errors.New("decode transaction: core info missing")
+	}
+	typed, err := decodeCoreInfo(protoTx.Info)
+	if err != nil {
+		return nil, err
+	}
+	if protoTx.Signature != nil {
+		typed.Signature = protoTx.Signature.Data
+	}
+	tx := typed.Transaction()
+	tx.ID = hex.EncodeToString(protoTx.Tranid)
+	return tx, nil
+}
+
+func decodeCoreInfo(info *protobuf.ProtoTransactionCoreInfo) (*TypedTransaction, error) {
+	switch {
+	case info.Version == nil:
+		return nil, errors.New("decode transaction: version missing")
+	case info.Nonce == nil:
+		return nil, errors.New("decode transaction: nonce missing")
+	case info.Gaslimit == nil:
+		return nil, errors.New("decode transaction: gas limit missing")
+	case len(info.Toaddr) != len(Address{}):
+		return nil, fmt.Errorf("decode transaction: address of %d bytes, want %d", len(info.Toaddr), len(Address{}))
+	}
+	amount, err := unpadBigInt("amount", info.Amount)
+	if err != nil {
+		return nil, err
+	}
+	gasPrice, err := unpadBigInt("gas price", info.Gasprice)
+	if err != nil {
+		return nil, err
+	}
+
+	typed := &TypedTransaction{
+		Version:  Version{ChainID: uint16(*info.Version >> 16), MsgVersion: uint16(*info.Version)},
+		Nonce:    *info.Nonce,
+		Amount:   amount,
+		GasPrice: gasPrice,
+		GasLimit: *info.Gaslimit,
+		Code:     string(info.Code),
+		Data:     string(info.Data),
+	}
+	copy(typed.ToAddr[:], info.Toaddr)
+	if info.Senderpubkey != nil {
+		typed.SenderPubKey = info.Senderpubkey.Data
+	}
+	return typed, nil
+}
+
+func unpadBigInt(name string, array *protobuf.ByteArray) (*big.Int, error) {
+	if array == nil {
+		return nil, fmt.Errorf("decode transaction: %s missing", name)
+	}
+	if len(array.Data) > amountBits/8 {
+		return nil, fmt.Errorf("decode transaction: %s of %d bytes", name, len(array.Data))
+	}
+	return new(big.Int).SetBytes(array.Data), nil
+}
@@ -17,8 +17,12 @@
 package transaction
 
 import (
+	"encoding/json"
+	"github.com/Zilliqa/gozilliqa-sdk/protobuf"
 	"github.com/Zilliqa/gozilliqa-sdk/util"
+	"github.com/golang/protobuf/proto"
 	"github.com/stretchr/testify/assert"
+	"os"
 	"strings"
 	"testing"
 )
@@ -39,3 +43,130 @@ func TestEncodeTransactionProto(t *testing.T) {
 	bytes, _ := EncodeTransactionProto(txParams)
 	assert.Equal(t, strings.ToUpper(util.EncodeHex(bytes)), "080010001A142E3C9B415B19AE4035503A06192A0FAD76E0424322230A210246E7178DC8253201101E18FD6F6EB9972451D121FC57AA2A06DD5C111E58DC6A2A120A100000000000000000000000000000271032120A100000000000000000000000000000006438E807")
 }
+
+func TestDecodeTransactionProto(t *testing.T) {
+	bytes := util.DecodeHex("080010001A142E3C9B415B19AE4035503A06192A0FAD76E0424322230A210246E7178DC8253201101E18FD6F6EB9972451D121FC57AA2A06DD5C111E58DC6A2A120A100000000000000000000000000000271032120A100000000000000000000000000000006438E807")
+	tx, err := DecodeTransactionProto(bytes)
+	assert.Nil(t, err, err)
+	assert.Equal(t, "0", tx.Version)
+	assert.Equal(t, "0", tx.Nonce)
+	assert.Equal(t, "2E3C9B415B19AE4035503A06192A0FAD76E04243", strings.ToUpper(tx.ToAddr))
+	assert.Equal(t, "0246e7178dc8253201101e18fd6f6eb9972451d121fc57aa2a06dd5c111e58dc6a", tx.SenderPubKey)
+	assert.Equal(t, "10000", tx.Amount)
+	assert.Equal(t, "100", tx.GasPrice)
+	assert.Equal(t, "1000", tx.GasLimit)
+	assert.Equal(t, "", tx.Data)
+
+	encoded, err := tx.Bytes()
+	assert.Nil(t, err, err)
+	assert.Equal(t, bytes, encoded)
+
+	_, err = DecodeTransactionProto([]byte{0xff})
+	assert.NotNil(t, err)
+	_, err = DecodeTransactionProto(bytes[:len(bytes)-3])
+	assert.NotNil(t, err)
+}
+
+func TestDecodeProtoTransaction(t *testing.T) {
+	data := `{"_tag":"SubmitCustomMintTransaction","params":[{"vname":"proxyTokenContract","type":"ByStr20","value":"0x39550ab45d74cce5fef70e857c1326b2d9bee096"},{"vname":"to","type":"ByStr20","value":"0x39550ab45d74cce5fef70e857c1326b2d9bee096"},{"vname":"value","type":"Uint128","value":"10000000"}]}`
+	signed := &Transaction{
+		Version:      "21823489",
+		Nonce:        "959",
+		Amount:       "0",
+		GasPrice:     "1000000000",
+		GasLimit:     "10000",
+		Signature:    "c0dcffb4f5ef80b9e426c16fc1fb62b31356219deb84c5689ab6a73915ea962c0bc4d4a49985803cd1db8aabb6870e8c749003cab41246e17493767acc6cca90",
+		SenderPubKey: "0246e7178dc8253201101e18fd6f6eb9972451d121fc57aa2a06dd5c111e58dc6a",
+		ToAddr:       "84eb5C96Bec8d29eDdFBe36865E9B7F26b816f0F",
+		Data:         json.RawMessage(data),
+	}
+	core, err := signed.Bytes()
+	assert.Nil(t, err, err)
+	hash, err := signed.Hash()
+	assert.Nil(t, err, err)
+	var info protobuf.ProtoTransactionCoreInfo
+	assert.Nil(t, proto.Unmarshal(core, &info))
+	bytes, err := proto.Marshal(&protobuf.ProtoTransaction{
+		Tranid:    util.DecodeHex(hash),
+		Info:      &info,
+		Signature: &protobuf.ByteArray{Data: util.DecodeHex(signed.Signature)},
+	})
+	assert.Nil(t, err, err)
+
+	tx, err := DecodeProtoTransaction(bytes)
+	assert.Nil(t, err, err)
+	assert.Equal(t, hash, tx.ID)
+	assert.Equal(t, signed.Signature, tx.Signature)
+	assert.Equal(t, "21823489", tx.Version)
+	assert.Equal(t, "959", tx.Nonce)
+	assert.Equal(t, data, tx.Data)
+	assert.Equal(t, signed.ToAddr, tx.ToAddr)
+	assertSigned(t, tx)
+	decodedHash, err := tx.Hash()
+	assert.Nil(t, err, err)
+	assert.Equal(t, hash, decodedHash)
+	expected, err := signed.Payload()
+	assert.Nil(t, err, err)
+	payload, err := tx.Payload()
+	assert.Nil(t, err, err)
+	assert.Equal(t, expected, payload)
+
+	_, err = DecodeProtoTransaction(util.DecodeHex("0a0100"))
+	assert.NotNil(t, err)
+
+	// amounts are 128 bits
+	info.Amount = &protobuf.ByteArray{Data: make([]byte, 17)}
+	bytes, err = proto.Marshal(&protobuf.ProtoTransaction{Info: &info})
+	assert.Nil(t, err, err)
+	_, err = DecodeProtoTransaction(bytes)
+	assert.NotNil(t, err)
+}
+
+// TestDecodeProtoTransaction_Vector decodes the same transaction, serialised by hand from
+// the wire format of protobuf/message.proto rather than by this package. Its ID was not
+// computed by a node, so it is only carried over; TestDecodeProtoTransaction_Node checks the
+// ID against one.
+func TestDecodeProtoTransaction_Vector(t *testing.T) {
+	tx, err := DecodeProtoTransaction(util.DecodeHex("0a203465c02af86cd92fa046f554d906e088796ff87a9e0ba602b9fda46b87faab82129203088180b40a10bf071a1484eb5c96bec8d29eddfbe36865e9b7f26b816f0f22230a210246e7178dc8253201101e18fd6f6eb9972451d121fc57aa2a06dd5c111e58dc6a2a120a100000000000000000000000000000000032120a100000000000000000000000003b9aca0038904e4aa1027b225f746167223a225375626d6974437573746f6d4d696e745472616e73616374696f6e222c22706172616d73223a5b7b22766e616d65223a2270726f7879546f6b656e436f6e7472616374222c2274797065223a2242795374723230222c2276616c7565223a22307833393535306162343564373463636535666566373065383537633133323662326439626565303936227d2c7b22766e616d65223a22746f222c2274797065223a2242795374723230222c2276616c7565223a22307833393535306162343564373463636535666566373065383537633133323662326439626565303936227d2c7b22766e616d65223a2276616c7565222c2274797065223a2255696e74313238222c2276616c7565223a223130303030303030227d5d7d1a420a40c0dcffb4f5ef80b9e426c16fc1fb62b31356219deb84c5689ab6a73915ea962c0bc4d4a49985803cd1db8aabb6870e8c749003cab41246e17493767acc6cca90"))
+	assert.Nil(t, err, err)
+	assert.Equal(t, "3465c02af86cd92fa046f554d906e088796ff87a9e0ba602b9fda46b87faab82", tx.ID)
+	assert.Equal(t, "21823489", tx.Version)
+	assert.Equal(t, "959", tx.Nonce)
+	assert.Equal(t, "0", tx.Amount)
+	assert.Equal(t, "1000000000", tx.GasPrice)
+	assert.Equal(t, "10000", tx.GasLimit)
+	assert.Equal(t, "84eb5C96Bec8d29eDdFBe36865E9B7F26b816f0F", tx.ToAddr)
+	assertSigned(t, tx)
+}
+
+// TestDecodeProtoTransaction_Node decodes a transaction read from the developer testnet, with
+// the ID the node computed for it.
+func TestDecodeProtoTransaction_Node(t *testing.T) {
+	if os.Getenv("CI") != "" {
+		t.Skip("Skipping testing in CI environment")
+	}
+	node := nodeTransaction(t)
+	core, err := node.Bytes()
+	assert.Nil(t, err, err)
+	var info protobuf.ProtoTransactionCoreInfo
+	assert.Nil(t, proto.Unmarshal(core, &info))
+	bytes, err := proto.Marshal(&protobuf.ProtoTransaction{
+		Tranid:    util.DecodeHex(nodeTxHash),
+		Info:      &info,
+		Signature: &protobuf.ByteArray{Data: util.DecodeHex(node.Signature)},
+	})
+	assert.Nil(t, err, err)
+
+	tx, err := DecodeProtoTransaction(bytes)
+	assert.Nil(t, err, err)
+	assert.Equal(t, nodeTxHash, tx.ID)
+	hash, err := tx.Hash()
+	assert.Nil(t, err, err)
+	assert.Equal(t, nodeTxHash, hash)
+	assertSigned(t, tx)
+	expected, err := node.Payload()
+	assert.Nil(t, err, err)
+	payload, err := tx.Payload()
+	assert.Nil(t, err, err)
+	assert.Equal(t, expected, payload)
+}
@@ -0,0 +1,254 @@
+/*
+ * Copyright (C) 2019 Zilliqa
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+package transaction
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"github.com/Zilliqa/gozilliqa-sdk/bech32"
+	"github.com/Zilliqa/gozilliqa-sdk/provider"
+	"github.com/Zilliqa/gozilliqa-sdk/util"
+	"github.com/Zilliqa/gozilliqa-sdk/validator"
+	"math"
+	"math/big"
+	"strconv"
+	"strings"
+)
+
+// Version is the version field of a transaction: the chain ID in the upper 16 bits and the
+// message version in the lower 16 bits.
+type Version struct {
+	ChainID    uint16
+	MsgVersion uint16
+}
+
+// NewVersion returns the version of a transaction on the chain chainID, like util.Pack.
+func NewVersion(chainID, msgVersion uint16) Version {
+	return Version{ChainID: chainID, MsgVersion: msgVersion}
+}
+
+// ParseVersion parses the decimal form of a packed version, e.g. "21823489" for chain 333.
+func ParseVersion(s string) (Version, error) {
+	v, err := strconv.ParseUint(s, 10, 32)
+	if err != nil {
+		return Version{}, fmt.Errorf("parse version %q: %w", s, err)
+	}
+	return Version{ChainID: uint16(v >> 16), MsgVersion: uint16(v)}, nil
+}
+
+func (v Version) Uint32() uint32 {
+	return uint32(v.ChainID)<<16 | uint32(v.MsgVersion)
+}
+
+func (v Version) String() string {
+	return strconv.FormatUint(uint64(v.Uint32()), 10)
+}
+
+// Address is a 20 byte account address.
+type Address [20]byte
+
+// ParseAddress parses a hex address, with or without 0x and in any case, or a bech32 address.
+// The empty string is the zero address, which deploys contracts.
+func ParseAddress(s string) (Address, error) {
+	var address Address
+	if s == "" {
+		return address, nil
+	}
+	hexAddress := s
+	if validator.IsBech32(s) {
+		var err error
+		if hexAddress, err = bech32.FromBech32Addr(s); err != nil {
+			return address, fmt.Errorf("parse address %q: %w", s, err)
+		}
+	}
+	hexAddress = strings.TrimPrefix(strings.TrimPrefix(hexAddress, "0x"), "0X")
+	b, err := hex.DecodeString(hexAddress)
+	if err != nil {
+		return address, fmt.Errorf("parse address %q: %w", s, err)
+	}
+	if len(b) != len(address) {
+		return address, fmt.Errorf("parse address %q: %d bytes, want %d", s, len(b), len(address))
+	}
+	copy(address[:], b)
+	return address, nil
+}
+
+// String returns the checksummed form of the address, with 0x.
+func (a Address) String() string {
+	return util.ToCheckSumAddress(a.Hex())
+}
+
+// Hex returns the address as lowercase hex, without 0x.
+func (a Address) Hex() string {
+	return hex.EncodeToString(a[:])
+}
+
+func (a Address) IsZero() bool {
+	return a == Address{}
+}
+
+// TypedTransaction is a Transaction with its amounts, nonce, gas limit, address and version
+// parsed. Its fields are checked once, when converting from a Transaction with Typed, instead
+// of each time the transaction is encoded, signed or sent.
+type TypedTransaction struct {
+	Version      Version
+	Nonce        uint64
+	ToAddr       Address
+	Amount       *big.Int
+	GasPrice     *big.Int
+	GasLimit     uint64
+	SenderPubKey []byte
+	Signature    []byte
+	Code         string
+	// Data is the JSON of the transition call or of the contract init parameters, or empty.
+	Data     string
+	Priority bool
+}
+
+// Typed parses the string fields of the transaction. An empty Nonce is parsed as 0, which is
+// never a valid nonce on the chain, so transactions that are not signed yet can be checked.
+func (t *Transaction) Typed() (*TypedTransaction, error) {
+	var err error
+	typed := &TypedTransaction{Code: t.Code, Priority: t.Priority}
+	if typed.Version, err = ParseVersion(t.Version); err != nil {
+		return nil, err
+	}
+	if t.Nonce != "" {
+		if typed.Nonce, err = strconv.ParseUint(t.Nonce, 10, 64); err != nil {
+			return nil, fmt.Errorf("parse nonce %q: %w", t.Nonce, err)
+		}
+	}
+	if typed.ToAddr, err = ParseAddress(t.ToAddr); err != nil {
+		return nil, err
+	}
+	if typed.Amount, err = parseAmount("amount", t.Amount); err != nil {
+		return nil, err
+	}
+	if typed.GasPrice, err = parseAmount("gas price", t.GasPrice); err != nil {
+		return nil, err
+	}
+	if typed.GasLimit, err = strconv.ParseUint(t.GasLimit, 10, 64); err != nil {
+		return nil, fmt.Errorf("parse gas limit %q: %w", t.GasLimit, err)
+	}
+	if typed.SenderPubKey, err = hex.DecodeString(strings.TrimPrefix(t.SenderPubKey, "0x")); err != nil {
+		return nil, fmt.Errorf("parse sender public key: %w", err)
+	}
+	if typed.Signature, err = hex.DecodeString(strings.TrimPrefix(t.Signature, "0x")); err != nil {
+		return nil, fmt.Errorf("parse signature: %w", err)
+	}
+	if typed.Data, err = t.encodedData(); err != nil {
+		return nil, err
+	}
+	return typed, nil
+}
+
+func parseAmount(name, s string) (*big.Int, error) {
+	amount, ok := new(big.Int).SetString(s, 10)
+	if !ok {
+		return nil, fmt.Errorf("parse %s %q: invalid number", name, s)
+	}
+	if amount.Sign() < 0 {
+		return nil, fmt.Errorf("parse %s %q: negative", name, s)
+	}
+	if amount.BitLen() > amountBits {
+		return nil, fmt.Errorf("parse %s %q: over %d bits", name, s, amountBits)
+	}
+	return amount, nil
+}
+
+// encodeData returns the JSON of the data of a transaction, or "" for the empty string. Data
+// that is already JSON is passed as json.RawMessage.
+func encodeData(data interface{}) (string, error) {
+	if raw, ok := data.(json.RawMessage); ok {
+		if len(raw) > 0 && !json.Valid(raw) {
+			return "", fmt.Errorf("encode data: invalid JSON %q", raw)
+		}
+		return string(raw), nil
+	}
+	b, err := json.Marshal(data)
+	if err != nil {
+		return "", fmt.Errorf("encode data: %w", err)
+	}
+	if string(b) == `""` {
+		return "", nil
+	}
+	return string(b), nil
+}
+
+// Transaction returns the string form of the transaction. The ID, receipt and status are not
+// part of a TypedTransaction and are left empty.
+func (typed *TypedTransaction) Transaction() *Transaction {
+	t := &Transaction{
+		Version:      typed.Version.String(),
+		Nonce:        strconv.FormatUint(typed.Nonce, 10),
+		Amount:       typed.amount().String(),
+		GasPrice:     typed.gasPrice().String(),
+		GasLimit:     strconv.FormatUint(typed.GasLimit, 10),
+		Signature:    hex.EncodeToString(typed.Signature),
+		SenderPubKey: hex.EncodeToString(typed.SenderPubKey),
+		ToAddr:       typed.ToAddr.String()[2:],
+		Code:         typed.Code,
+		Data:         typed.Data,
+		Priority:     typed.Priority,
+		dataJSON:     true,
+	}
+	return t
+}
+
+// Payload returns the transaction in the form CreateTransaction sends it.
+func (typed *TypedTransaction) Payload() (provider.TransactionPayload, error) {
+	if typed.Nonce > math.MaxInt32 && strconv.IntSize == 32 {
+		return provider.TransactionPayload{}, fmt.Errorf("nonce %d does not fit into an int", typed.Nonce)
+	}
+	p := provider.TransactionPayload{
+		Version:   int(typed.Version.Uint32()),
+		Nonce:     int(typed.Nonce),
+		ToAddr:    typed.ToAddr.String()[2:],
+		Amount:    typed.amount().String(),
+		PubKey:    hex.EncodeToString(typed.SenderPubKey),
+		GasPrice:  typed.gasPrice().String(),
+		GasLimit:  strconv.FormatUint(typed.GasLimit, 10),
+		Code:      typed.Code,
+		Data:      typed.Data,
+		Signature: hex.EncodeToString(typed.Signature),
+		Priority:  typed.Priority,
+	}
+	if typed.ToAddr.IsZero() {
+		p.ToAddr = "0x0000000000000000000000000000000000000000"
+	}
+	return p, nil
+}
+
+// Bytes returns the protobuf encoding of the transaction that is signed.
+func (typed *TypedTransaction) Bytes() ([]byte, error) {
+	return encodeTypedProto(typed)
+}
+
+func (typed *TypedTransaction) amount() *big.Int {
+	if typed.Amount == nil {
+		return new(big.Int)
+	}
+	return typed.Amount
+}
+
+func (typed *TypedTransaction) gasPrice() *big.Int {
+	if typed.GasPrice == nil {
+		return new(big.Int)
+	}
+	return typed.GasPrice
+}
@@ -0,0 +1,146 @@
+/*
+ * Copyright (C) 2019 Zilliqa
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+package transaction
+
+import (
+	"encoding/json"
+	"github.com/Zilliqa/gozilliqa-sdk/provider"
+	"github.com/Zilliqa/gozilliqa-sdk/util"
+	"github.com/stretchr/testify/assert"
+	"math/big"
+	"testing"
+)
+
+func TestParseVersion(t *testing.T) {
+	version, err := ParseVersion("21823489")
+	assert.Nil(t, err, err)
+	assert.Equal(t, NewVersion(333, 1), version)
+	assert.Equal(t, uint32(util.Pack(333, 1)), version.Uint32())
+	assert.Equal(t, "21823489", version.String())
+
+	_, err = ParseVersion("4294967296")
+	assert.NotNil(t, err)
+	_, err = ParseVersion("")
+	assert.NotNil(t, err)
+}
+
+func TestParseAddress(t *testing.T) {
+	for _, s := range []string{
+		"0x4BAF5faDA8e5Db92C3d3242618c5B47133AE003C",
+		"4baf5fada8e5db92c3d3242618c5b47133ae003c",
+		"zil1fwh4ltdguhde9s7nysnp33d5wye6uqpugufkz7",
+	} {
+		address, err := ParseAddress(s)
+		assert.Nil(t, err, err)
+		assert.Equal(t, "0x4BAF5faDA8e5Db92C3d3242618c5B47133AE003C", address.String(), s)
+		assert.Equal(t, "4baf5fada8e5db92c3d3242618c5b47133ae003c", address.Hex())
+	}
+
+	address, err := ParseAddress("")
+	assert.Nil(t, err, err)
+	assert.True(t, address.IsZero())
+	_, err = ParseAddress("0x4BAF5faDA8e5Db92C3d3242618c5B47133AE00")
+	assert.NotNil(t, err)
+	_, err = ParseAddress("0xzz")
+	assert.NotNil(t, err)
+}
+
+func TestTransaction_Typed(t *testing.T) {
+	tx := &Transaction{
+		Version:      "21823489",
+		Nonce:        "4294967297",
+		ToAddr:       "4BAF5faDA8e5Db92C3d3242618c5B47133AE003C",
+		Amount:       "100000000000000000000000",
+		GasPrice:     "2000000000",
+		GasLimit:     "50",
+		SenderPubKey: "0246e7178dc8253201101e18fd6f6eb9972451d121fc57aa2a06dd5c111e58dc6a",
+		Data:         []provider.Value{{VName: "to", Type: "ByStr20", Value: "0x4baf5fada8e5db92c3d3242618c5b47133ae003c"}},
+	}
+	typed, err := tx.Typed()
+	assert.Nil(t, err, err)
+	assert.Equal(t, uint16(333), typed.Version.ChainID)
+	assert.Equal(t, uint64(4294967297), typed.Nonce)
+	amount, _ := new(big.Int).SetString("100000000000000000000000", 10)
+	assert.Equal(t, amount, typed.Amount)
+	assert.Equal(t, uint64(50), typed.GasLimit)
+	assert.Equal(t, `[{"vname":"to","type":"ByStr20","value":"0x4baf5fada8e5db92c3d3242618c5b47133ae003c"}]`, typed.Data)
+
+	// the nonce is not truncated to 32 bits anymore
+	payload, err := tx.Payload()
+	assert.Nil(t, err, err)
+	assert.Equal(t, 4294967297, payload.Nonce)
+	assert.Equal(t, tx.ToTransactionPayload(), payload)
+
+	// the typed form encodes and converts back to the same transaction
+	expected, err := tx.Bytes()
+	assert.Nil(t, err, err)
+	encoded, err := typed.Bytes()
+	assert.Nil(t, err, err)
+	assert.Equal(t, expected, encoded)
+	back := typed.Transaction()
+	assert.Equal(t, "0x"+back.ToAddr, typed.ToAddr.String())
+	roundTrip, err := back.Bytes()
+	assert.Nil(t, err, err)
+	assert.Equal(t, expected, roundTrip)
+	backPayload, err := back.Payload()
+	assert.Nil(t, err, err)
+	assert.Equal(t, payload, backPayload)
+}
+
+func TestTransaction_TypedErrors(t *testing.T) {
+	valid := Transaction{Version: "65537", Nonce: "1", Amount: "1", GasPrice: "1", GasLimit: "1"}
+	_, err := valid.Typed()
+	assert.Nil(t, err, err)
+
+	for name, update := range map[string]func(tx *Transaction){
+		"version":        func(tx *Transaction) { tx.Version = "v1" },
+		"nonce":          func(tx *Transaction) { tx.Nonce = "-1" },
+		"amount":         func(tx *Transaction) { tx.Amount = "1.5" },
+		"negative":       func(tx *Transaction) { tx.Amount = "-1" },
+		"amount bits":    func(tx *Transaction) { tx.Amount = "340282366920938463463374607431768211456" },
+		"gas price bits": func(tx *Transaction) { tx.GasPrice = "340282366920938463463374607431768211456" },
+		"gas price":      func(tx *Transaction) { tx.GasPrice = "" },
+		"gas limit":      func(tx *Transaction) { tx.GasLimit = "18446744073709551616" },
+		"address":        func(tx *Transaction) { tx.ToAddr = "0x1234" },
+		"pub key":        func(tx *Transaction) { tx.SenderPubKey = "xyz" },
+		"data":           func(tx *Transaction) { tx.Data = json.RawMessage("{") },
+		"data type":      func(tx *Transaction) { tx.Data = make(chan int) },
+	} {
+		tx := valid
+		update(&tx)
+		_, err := tx.Typed()
+		assert.NotNil(t, err, name)
+		_, err = tx.Payload()
+		assert.NotNil(t, err, name)
+	}
+
+	valid.Nonce = ""
+	_, err = valid.Payload()
+	assert.NotNil(t, err)
+
+	// the largest 128-bit amount is encoded, one more is not
+	typed, err := (&Transaction{Version: "65537", Amount: "340282366920938463463374607431768211455", GasPrice: "1", GasLimit: "1"}).Typed()
+	assert.Nil(t, err, err)
+	_, err = typed.Bytes()
+	assert.Nil(t, err, err)
+	typed.Amount.Add(typed.Amount, big.NewInt(1))
+	_, err = typed.Bytes()
+	assert.NotNil(t, err)
+	typed.Amount, typed.GasPrice = big.NewInt(1), typed.Amount
+	_, err = typed.Bytes()
+	assert.NotNil(t, err)
+}
@@ -18,12 +18,14 @@ package workpool
 
 import (
 	"context"
-	"fmt"
+	"github.com/Zilliqa/gozilliqa-sdk/logging"
 	"golang.org/x/sync/semaphore"
 	"time"
 )
 
 type WorkerPool struct {
+	// Logger receives the progress of Poll. It is logging.Nop unless set.
+	Logger     logging.Logger
 	maxWorkers int64
 	sem        *semaphore.Weighted
 	takes      map[string]Task
@@ -61,19 +63,29 @@ func (wp *WorkerPool) Top() Task {
 
 }
 
+// Poll runs queued tasks with at most maxWorkers running at a time. It returns once quit
+// is closed or ctx is done; tasks that are already running are not interrupted.
 func (wp *WorkerPool) Poll(ctx context.Context, quit <-chan struct{}) {
 	for {
 		select {
 		case <-quit:
-			fmt.Println("quit now..")
-			break
+			logging.OrNop(wp.Logger).Info("worker pool quit")
+			return
+		case <-ctx.Done():
+			return
 		default:
 			task := wp.Top()
 			if task == nil {
-				time.Sleep(time.Second * 3)
+				timer := time.NewTimer(time.Second * 3)
+				select {
+				case <-quit:
+				case <-ctx.Done():
+				case <-timer.C:
+				}
+				timer.Stop()
 			} else {
 				if err := wp.sem.Acquire(ctx, 1); err != nil {
-					break
+					return
 				}
 				go func() {
 					defer wp.sem.Release(1)
@@ -0,0 +1,374 @@
+/*
+ * Copyright (C) 2019 Zilliqa
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+package zilliqatest
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"sync"
+)
+
+// Mode decides whether a Recorder talks to the network.
+type Mode int
+
+const (
+	// ModeReplay answers calls from the cassette only.
+	ModeReplay Mode = iota
+	// ModeRecord forwards calls to the real node and adds every exchange to the cassette.
+	ModeRecord
+)
+
+// Redacted replaces the value of every redacted field in a cassette.
+const Redacted = "REDACTED"
+
+// Interaction is one recorded JSON-RPC call. Response holds the result or error member of the
+// node's answer; the request id is not recorded, as it differs between runs.
+type Interaction struct {
+	Method   string          `json:"method"`
+	Params   json.RawMessage `json:"params"`
+	Response json.RawMessage `json:"response"`
+}
+
+// Cassette is the content of a cassette file. Comment says where the interactions come from,
+// e.g. that they were written by hand rather than recorded.
+type Cassette struct {
+	Comment      string         `json:"comment,omitempty"`
+	Interactions []*Interaction `json:"interactions"`
+}
+
+// Recorder is an http.RoundTripper that records the JSON-RPC calls a provider makes into a
+// cassette file, or replays them from it. Plain calls, batches and GetSmartContractSubState are
+// all split into single calls, which are matched by method and params. HTTP headers are never
+// recorded, so API keys do not end up in cassettes.
+//
+//	recorder, err := zilliqatest.NewRecorder("testdata/txblock.json", zilliqatest.ModeReplay)
+//	p := provider.NewProviderWithOptions("https://api.zilliqa.com/", provider.WithRoundTripper(recorder))
+type Recorder struct {
+	path      string
+	mode      Mode
+	strict    bool
+	redact    map[string]bool
+	transport http.RoundTripper
+
+	mu       sync.Mutex
+	cassette *Cassette
+	used     []bool
+}
+
+// RecorderOption configures a Recorder created by NewRecorder.
+type RecorderOption func(r *Recorder)
+
+// WithStrict makes a replaying recorder fail every call that is not in the cassette. Otherwise
+// such calls are forwarded to the real node without being recorded.
+func WithStrict() RecorderOption {
+	return func(r *Recorder) {
+		r.strict = true
+	}
+}
+
+// WithRedactedFields replaces the values of the given JSON object fields, in params and in
+// responses, by Redacted. Calls are matched after redaction, so replay still works.
+func WithRedactedFields(fields ...string) RecorderOption {
+	return func(r *Recorder) {
+		for _, field := range fields {
+			r.redact[field] = true
+		}
+	}
+}
+
+// WithTransport sets the RoundTripper used to reach the real node. The default is http.DefaultTransport.
+func WithTransport(transport http.RoundTripper) RecorderOption {
+	return func(r *Recorder) {
+		r.transport = transport
+	}
+}
+
+// NewRecorder creates a recorder for the cassette at path. In ModeReplay the cassette must exist;
+// in ModeRecord it is created, or extended if it exists, by Save.
+func NewRecorder(path string, mode Mode, opts ...RecorderOption) (*Recorder, error) {
+	r := &Recorder{
+		path:      path,
+		mode:      mode,
+		redact:    make(map[string]bool),
+		transport: http.DefaultTransport,
+		cassette:  &Cassette{},
+	}
+	for _, opt := range opts {
+		opt(r)
+	}
+
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		if mode == ModeReplay {
+			return nil, fmt.Errorf("zilliqatest: load cassette: %s", err)
+		}
+	} else if err := json.Unmarshal(data, r.cassette); err != nil {
+		return nil, fmt.Errorf("zilliqatest: load cassette %s: %s", path, err)
+	}
+	// cassettes may be edited by hand, so params are brought into the form calls are matched in
+	for _, interaction := range r.cassette.Interactions {
+		if interaction.Params, err = r.canonical(interaction.Params); err != nil {
+			return nil, err
+		}
+	}
+	r.used = make([]bool, len(r.cassette.Interactions))
+	return r, nil
+}
+
+// Save writes the cassette to its file.
+func (r *Recorder) Save() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	data, err := json.MarshalIndent(r.cassette, "", "  ")
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(r.path, append(data, '\n'), 0644)
+}
+
+// Unused returns the interactions of the cassette that were not replayed.
+func (r *Recorder) Unused() []*Interaction {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var unused []*Interaction
+	for i, interaction := range r.cassette.Interactions {
+		if !r.used[i] {
+			unused = append(unused, interaction)
+		}
+	}
+	return unused
+}
+
+type cassetteRequest struct {
+	ID     json.RawMessage `json:"id"`
+	Method string          `json:"method"`
+	Params json.RawMessage `json:"params"`
+}
+
+func (r *Recorder) RoundTrip(request *http.Request) (*http.Response, error) {
+	var body []byte
+	if request.Body != nil {
+		var err error
+		if body, err = ioutil.ReadAll(request.Body); err != nil {
+			return nil, err
+		}
+		_ = request.Body.Close()
+	}
+
+	trimmed := bytes.TrimSpace(body)
+	batch := len(trimmed) > 0 && trimmed[0] == '['
+	var calls []*cassetteRequest
+	if batch {
+		if err := json.Unmarshal(trimmed, &calls); err != nil {
+			return nil, fmt.Errorf("zilliqatest: decode request: %s", err)
+		}
+	} else {
+		var call *cassetteRequest
+		if err := json.Unmarshal(trimmed, &call); err != nil || call == nil {
+			return nil, fmt.Errorf("zilliqatest: decode request: %s", err)
+		}
+		calls = []*cassetteRequest{call}
+	}
+	for _, call := range calls {
+		params, err := r.canonical(call.Params)
+		if err != nil {
+			return nil, err
+		}
+		call.Params = params
+	}
+
+	if r.mode == ModeRecord {
+		return r.record(request, body, calls, batch)
+	}
+	return r.replay(request, body, calls, batch)
+}
+
+func (r *Recorder) replay(request *http.Request, body []byte, calls []*cassetteRequest, batch bool) (*http.Response, error) {
+	responses := make([]json.RawMessage, 0, len(calls))
+	r.mu.Lock()
+	for _, call := range calls {
+		response := r.find(call)
+		if response == nil {
+			r.mu.Unlock()
+			if r.strict {
+				return nil, fmt.Errorf("zilliqatest: unexpected call %s(%s)", call.Method, call.Params)
+			}
+			return r.forward(request, body)
+		}
+		responses = append(responses, withID(response, call.ID))
+	}
+	r.mu.Unlock()
+
+	if batch {
+		return newResponse(request, http.StatusOK, mustMarshal(responses)), nil
+	}
+	return newResponse(request, http.StatusOK, responses[0]), nil
+}
+
+// find returns the response recorded for call. Interactions are replayed in recorded order;
+// once all matching ones are used, the last one is repeated, e.g. for polling.
+func (r *Recorder) find(call *cassetteRequest) json.RawMessage {
+	last := -1
+	for i, interaction := range r.cassette.Interactions {
+		if interaction.Method != call.Method || !bytes.Equal(interaction.Params, call.Params) {
+			continue
+		}
+		if !r.used[i] {
+			r.used[i] = true
+			return interaction.Response
+		}
+		last = i
+	}
+	if last < 0 {
+		return nil
+	}
+	return r.cassette.Interactions[last].Response
+}
+
+func (r *Recorder) record(request *http.Request, body []byte, calls []*cassetteRequest, batch bool) (*http.Response, error) {
+	response, err := r.forward(request, body)
+	if err != nil {
+		return nil, err
+	}
+	data, err := ioutil.ReadAll(response.Body)
+	_ = response.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	response.Body = ioutil.NopCloser(bytes.NewReader(data))
+
+	// only well formed JSON-RPC answers are recorded; anything else is passed through as is
+	var answers []map[string]json.RawMessage
+	if batch {
+		if json.Unmarshal(data, &answers) != nil {
+			return response, nil
+		}
+	} else {
+		var answer map[string]json.RawMessage
+		if json.Unmarshal(data, &answer) != nil {
+			return response, nil
+		}
+		answers = append(answers, answer)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, call := range calls {
+		for _, answer := range answers {
+			if batch && !bytes.Equal(answer["id"], call.ID) {
+				continue
+			}
+			delete(answer, "id")
+			delete(answer, "jsonrpc")
+			recorded, err := r.canonical(mustMarshal(answer))
+			if err != nil {
+				return nil, err
+			}
+			r.cassette.Interactions = append(r.cassette.Interactions, &Interaction{
+				Method:   call.Method,
+				Params:   call.Params,
+				Response: recorded,
+			})
+			r.used = append(r.used, true)
+			break
+		}
+	}
+	return response, nil
+}
+
+func (r *Recorder) forward(request *http.Request, body []byte) (*http.Response, error) {
+	if r.transport == nil {
+		return nil, errors.New("zilliqatest: no transport to forward the call to")
+	}
+	forwarded := request.Clone(request.Context())
+	forwarded.Body = ioutil.NopCloser(bytes.NewReader(body))
+	forwarded.ContentLength = int64(len(body))
+	return r.transport.RoundTrip(forwarded)
+}
+
+// canonical redacts data and re-encodes it with sorted object keys, so that equal JSON values
+// compare equal byte by byte.
+func (r *Recorder) canonical(data json.RawMessage) (json.RawMessage, error) {
+	if len(bytes.TrimSpace(data)) == 0 {
+		return json.RawMessage("null"), nil
+	}
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	var value interface{}
+	if err := decoder.Decode(&value); err != nil {
+		return nil, fmt.Errorf("zilliqatest: decode %s: %s", data, err)
+	}
+	return mustMarshal(r.redactValue(value)), nil
+}
+
+func (r *Recorder) redactValue(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for key, field := range v {
+			if r.redact[key] {
+				v[key] = Redacted
+			} else {
+				v[key] = r.redactValue(field)
+			}
+		}
+	case []interface{}:
+		for i, item := range v {
+			v[i] = r.redactValue(item)
+		}
+	}
+	return value
+}
+
+// withID turns a recorded response into a JSON-RPC response to the request with the given id.
+func withID(response json.RawMessage, id json.RawMessage) json.RawMessage {
+	var answer map[string]json.RawMessage
+	if err := json.Unmarshal(response, &answer); err != nil {
+		answer = map[string]json.RawMessage{}
+	}
+	if len(id) == 0 {
+		id = json.RawMessage("null")
+	}
+	answer["id"] = id
+	answer["jsonrpc"] = json.RawMessage(`"2.0"`)
+	return mustMarshal(answer)
+}
+
+func newResponse(request *http.Request, status int, body []byte) *http.Response {
+	return &http.Response{
+		Status:        http.StatusText(status),
+		StatusCode:    status,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        http.Header{"Content-Type": []string{"application/json"}},
+		Body:          ioutil.NopCloser(bytes.NewReader(body)),
+		ContentLength: int64(len(body)),
+		Request:       request,
+	}
+}
+
+func mustMarshal(v interface{}) json.RawMessage {
+	b, err := json.Marshal(v)
+	if err != nil {
+		panic(fmt.Sprintf("zilliqatest: %s", err))
+	}
+	return b
+}
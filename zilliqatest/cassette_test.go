@@ -0,0 +1,91 @@
+/*
+ * Copyright (C) 2019 Zilliqa
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+package zilliqatest_test
+
+import (
+	"encoding/json"
+	"github.com/Zilliqa/gozilliqa-sdk/provider"
+	. "github.com/Zilliqa/gozilliqa-sdk/zilliqatest"
+	"github.com/stretchr/testify/assert"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestRecorder(t *testing.T) {
+	dir, err := ioutil.TempDir("", "cassette")
+	assert.Nil(t, err, err)
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "cassette.json")
+
+	server := NewServer()
+	recorder, err := NewRecorder(path, ModeRecord, WithRedactedFields("BlockHash"))
+	assert.Nil(t, err, err)
+	p := provider.NewProviderWithOptions(server.URL, provider.WithRoundTripper(recorder), provider.WithHeader("X-Api-Key", "secret"))
+
+	recorded, err := p.GetTxBlockTyped("0")
+	assert.Nil(t, err, err)
+	_, err = p.NewBatch().GetTxBlock("0").GetBalance("4baf5fada8e5db92c3d3242618c5b47133ae003c").Send()
+	assert.Nil(t, err, err)
+	assert.Nil(t, recorder.Save())
+	server.Close()
+
+	data, _ := ioutil.ReadFile(path)
+	assert.False(t, strings.Contains(string(data), "secret"))
+	assert.False(t, strings.Contains(string(data), recorded.Body.BlockHash))
+	var cassette Cassette
+	assert.Nil(t, json.Unmarshal(data, &cassette))
+	assert.Equal(t, 3, len(cassette.Interactions))
+
+	// the server is gone, every answer comes from the cassette
+	replayer, err := NewRecorder(path, ModeReplay, WithStrict(), WithRedactedFields("BlockHash"))
+	assert.Nil(t, err, err)
+	p = provider.NewProviderWithOptions(server.URL, provider.WithRoundTripper(replayer))
+	block, err := p.GetTxBlockTyped("0")
+	assert.Nil(t, err, err)
+	assert.Equal(t, recorded.Header, block.Header)
+	assert.Equal(t, Redacted, block.Body.BlockHash)
+
+	results, err := p.NewBatch().GetBalance("4baf5fada8e5db92c3d3242618c5b47133ae003c").GetTxBlock("0").Send()
+	assert.Nil(t, err, err)
+	assert.NotNil(t, results[0].Err)
+	assert.Nil(t, results[1].Err)
+	assert.Equal(t, 0, len(replayer.Unused()))
+
+	_, err = p.GetTxBlock("1")
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "unexpected call GetTxBlock")
+}
+
+func TestRecorder_Comment(t *testing.T) {
+	dir, err := ioutil.TempDir("", "cassette")
+	assert.Nil(t, err, err)
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "cassette.json")
+	assert.Nil(t, ioutil.WriteFile(path, []byte(`{"comment":"written by hand","interactions":[]}`), 0644))
+
+	// recording more keeps the description of the cassette
+	recorder, err := NewRecorder(path, ModeRecord)
+	assert.Nil(t, err, err)
+	assert.Nil(t, recorder.Save())
+	data, _ := ioutil.ReadFile(path)
+	var cassette Cassette
+	assert.Nil(t, json.Unmarshal(data, &cassette))
+	assert.Equal(t, "written by hand", cassette.Comment)
+}
@@ -0,0 +1,508 @@
+/*
+ * Copyright (C) 2019 Zilliqa
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+package zilliqatest
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"github.com/Zilliqa/gozilliqa-sdk/provider"
+	go_schnorr "github.com/Zilliqa/gozilliqa-sdk/schnorr"
+	"github.com/Zilliqa/gozilliqa-sdk/transaction"
+	"github.com/Zilliqa/gozilliqa-sdk/util"
+	"io/ioutil"
+	"math/big"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// error codes used by the Zilliqa node
+const (
+	codeMiscError         = -1
+	codeInvalidAddress    = -5
+	codeInvalidParameter  = -8
+	codeDatabaseError     = -20
+	codeVerifyRejected    = -26
+	codeInvalidRequest    = -32600
+	codeMethodNotFound    = -32601
+	codeInvalidParams     = -32602
+	codeParseError        = -32700
+	accountNotCreated     = "Account is not created"
+	txnNotPresent         = "Txn Hash not Present"
+	txBlockNoTransactions = "TxBlock has no transactions"
+)
+
+type rpcRequest struct {
+	ID     json.RawMessage   `json:"id"`
+	Method string            `json:"method"`
+	Params []json.RawMessage `json:"params"`
+}
+
+type rpcResponse struct {
+	ID      json.RawMessage `json:"id"`
+	Jsonrpc string          `json:"jsonrpc"`
+	Result  interface{}     `json:"result,omitempty"`
+	Error   *rpcError       `json:"error,omitempty"`
+}
+
+type rpcError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+func newError(code int, format string, a ...interface{}) *rpcError {
+	return &rpcError{Code: code, Message: fmt.Sprintf(format, a...)}
+}
+
+type handler func(s *Server, params []json.RawMessage) (interface{}, *rpcError)
+
+var handlers = map[string]handler{
+	"GetNetworkId":                        getNetworkId,
+	"GetMinimumGasPrice":                  getMinimumGasPrice,
+	"GetBlockchainInfo":                   getBlockchainInfo,
+	"GetBalance":                          getBalance,
+	"CreateTransaction":                   createTransaction,
+	"GetTransaction":                      getTransaction,
+	"GetPendingTxn":                       getPendingTxn,
+	"GetTransactionStatus":                getTransactionStatus,
+	"GetRecentTransactions":               getRecentTransactions,
+	"GetNumTxBlocks":                      getNumTxBlocks,
+	"GetNumTransactions":                  getNumTransactions,
+	"GetLatestTxBlock":                    getLatestTxBlock,
+	"GetTxBlock":                          getTxBlock,
+	"GetTransactionsForTxBlock":           getTransactionsForTxBlock,
+	"GetTxnBodiesForTxBlock":              getTxnBodiesForTxBlock,
+	"GetContractAddressFromTransactionID": getContractAddressFromTransactionID,
+	"GetSmartContractCode":                getSmartContractCode,
+	"GetSmartContractInit":                getSmartContractInit,
+}
+
+func (s *Server) serveHTTP(w http.ResponseWriter, r *http.Request) {
+	body, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	s.mu.Lock()
+	var result interface{}
+	if trimmed := bytes.TrimSpace(body); len(trimmed) > 0 && trimmed[0] == '[' {
+		var requests []*rpcRequest
+		if err := json.Unmarshal(trimmed, &requests); err != nil || len(requests) == 0 {
+			result = &rpcResponse{Jsonrpc: "2.0", Error: newError(codeParseError, "Parse error")}
+		} else {
+			responses := make([]*rpcResponse, 0, len(requests))
+			for _, request := range requests {
+				responses = append(responses, s.handle(request))
+			}
+			result = responses
+		}
+	} else {
+		var request *rpcRequest
+		if err := json.Unmarshal(trimmed, &request); err != nil || request == nil {
+			result = &rpcResponse{Jsonrpc: "2.0", Error: newError(codeParseError, "Parse error")}
+		} else {
+			result = s.handle(request)
+		}
+	}
+	s.mu.Unlock()
+
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(result)
+}
+
+func (s *Server) handle(request *rpcRequest) *rpcResponse {
+	response := &rpcResponse{ID: request.ID, Jsonrpc: "2.0"}
+	if request.Method == "" {
+		response.Error = newError(codeInvalidRequest, "INVALID_JSON_REQUEST: The JSON sent is not a valid JSON-RPC Request object")
+		return response
+	}
+	h, ok := handlers[request.Method]
+	if !ok {
+		response.Error = newError(codeMethodNotFound, "METHOD_NOT_FOUND: The method being requested is not available on this server")
+		return response
+	}
+	response.Result, response.Error = h(s, request.Params)
+	return response
+}
+
+// stringParam decodes the single string parameter of a call.
+func stringParam(params []json.RawMessage) (string, *rpcError) {
+	if len(params) != 1 {
+		return "", newError(codeInvalidParams, "INVALID_PARAMS: Invalid method parameters (invalid name and/or type) recognised")
+	}
+	var value string
+	if err := json.Unmarshal(params[0], &value); err != nil {
+		return "", newError(codeInvalidParams, "INVALID_PARAMS: Invalid method parameters (invalid name and/or type) recognised")
+	}
+	return value, nil
+}
+
+// blockParam decodes the block number parameter of a call and looks the block up.
+func (s *Server) blockParam(params []json.RawMessage) (uint64, *rpcError) {
+	value, err := stringParam(params)
+	if err != nil {
+		return 0, err
+	}
+	blockNum, e := strconv.ParseUint(value, 10, 64)
+	if e != nil {
+		return 0, newError(codeInvalidParameter, "Invalid block number")
+	}
+	if blockNum >= uint64(len(s.blocks)) {
+		return 0, newError(codeInvalidParameter, "TxBlock does not exist")
+	}
+	return blockNum, nil
+}
+
+func getNetworkId(s *Server, params []json.RawMessage) (interface{}, *rpcError) {
+	return strconv.Itoa(s.chainID), nil
+}
+
+func getMinimumGasPrice(s *Server, params []json.RawMessage) (interface{}, *rpcError) {
+	return s.minGasPrice.String(), nil
+}
+
+func getBlockchainInfo(s *Server, params []json.RawMessage) (interface{}, *rpcError) {
+	numTxBlocks := uint64(len(s.blocks))
+	return &provider.BlockchainInfo{
+		CurrentDSEpoch:    strconv.FormatUint((numTxBlocks-1)/100, 10),
+		CurrentMiniEpoch:  strconv.FormatUint(numTxBlocks, 10),
+		NumDSBlocks:       strconv.FormatUint((numTxBlocks-1)/100+1, 10),
+		NumPeers:          1,
+		NumTransactions:   strconv.Itoa(s.numTransactions()),
+		NumTxBlocks:       strconv.FormatUint(numTxBlocks, 10),
+		NumTxnsDSEpoch:    "0",
+		NumTxnsTxEpoch:    strconv.Itoa(len(s.blockTxns[numTxBlocks-1])),
+		ShardingStructure: provider.ShardingStructure{NumPeers: []uint32{1}},
+	}, nil
+}
+
+func (s *Server) numTransactions() int {
+	n := 0
+	for _, txns := range s.blockTxns {
+		n += len(txns)
+	}
+	return n
+}
+
+func getBalance(s *Server, params []json.RawMessage) (interface{}, *rpcError) {
+	address, err := stringParam(params)
+	if err != nil {
+		return nil, err
+	}
+	a := s.account(normalizeAddress(address), false)
+	if a == nil {
+		return nil, newError(codeInvalidAddress, accountNotCreated)
+	}
+	return map[string]interface{}{
+		"balance": a.balance.String(),
+		"nonce":   a.nonce,
+	}, nil
+}
+
+func createTransaction(s *Server, params []json.RawMessage) (interface{}, *rpcError) {
+	if len(params) != 1 {
+		return nil, newError(codeInvalidParams, "INVALID_PARAMS: Invalid method parameters (invalid name and/or type) recognised")
+	}
+	var payload provider.TransactionPayload
+	if err := json.Unmarshal(params[0], &payload); err != nil {
+		return nil, newError(codeInvalidParameter, "Invalid Tx Json")
+	}
+	amount, ok := new(big.Int).SetString(payload.Amount, 10)
+	if !ok {
+		return nil, newError(codeInvalidParameter, "Invalid amount %q", payload.Amount)
+	}
+	gasPrice, ok := new(big.Int).SetString(payload.GasPrice, 10)
+	if !ok {
+		return nil, newError(codeInvalidParameter, "Invalid gas price %q", payload.GasPrice)
+	}
+	gasLimit, ok := new(big.Int).SetString(payload.GasLimit, 10)
+	if !ok {
+		return nil, newError(codeInvalidParameter, "Invalid gas limit %q", payload.GasLimit)
+	}
+
+	if payload.Version>>16 != s.chainID {
+		return nil, newError(codeVerifyRejected, "CHAIN_ID incorrect")
+	}
+
+	// the signature covers the protobuf encoding of the transaction, which also yields its id
+	message, err := transaction.EncodeTransactionProto(transaction.TxParams{
+		Version:      strconv.Itoa(payload.Version),
+		Nonce:        strconv.Itoa(payload.Nonce),
+		Amount:       payload.Amount,
+		GasPrice:     payload.GasPrice,
+		GasLimit:     payload.GasLimit,
+		SenderPubKey: payload.PubKey,
+		ToAddr:       payload.ToAddr,
+		Code:         payload.Code,
+		Data:         payload.Data,
+	})
+	if err != nil {
+		return nil, newError(codeInvalidParameter, "Invalid Tx Json: %s", err)
+	}
+	signature := util.DecodeHex(payload.Signature)
+	if len(signature) != 64 || !go_schnorr.Verify(util.DecodeHex(payload.PubKey), message, signature[:32], signature[32:]) {
+		return nil, newError(codeVerifyRejected, "Unable to verify transaction")
+	}
+	id := util.EncodeHex(util.Sha256(message))
+	if _, ok := s.txns[id]; ok {
+		return nil, newError(codeVerifyRejected, "Txn already present")
+	}
+
+	if gasPrice.Cmp(s.minGasPrice) < 0 {
+		return nil, newError(codeVerifyRejected, "GasPrice %s lower than minimum allowable %s", gasPrice, s.minGasPrice)
+	}
+
+	sender := senderAddress(payload.PubKey)
+	a := s.account(sender, false)
+	if a == nil {
+		return nil, newError(codeVerifyRejected, "The sender of the txn has no balance")
+	}
+
+	// pending transactions of the sender count as if they were already mined
+	nonce := a.nonce
+	spent := new(big.Int)
+	for _, t := range s.pending {
+		if t.sender == sender {
+			nonce = t.nonce
+			spent.Add(spent, new(big.Int).Add(t.amount, t.fee))
+		}
+	}
+	if uint64(payload.Nonce) != nonce+1 {
+		return nil, newError(codeVerifyRejected, "Nonce (%d) lower than or not equal to the expected nonce (%d)", payload.Nonce, nonce+1)
+	}
+	fee := new(big.Int).Mul(gasPrice, gasLimit)
+	if new(big.Int).Add(spent, new(big.Int).Add(amount, fee)).Cmp(a.balance) > 0 {
+		return nil, newError(codeVerifyRejected, "Insufficient Balance")
+	}
+
+	t := &txn{
+		result: &provider.TransactionResult{
+			ID:           id,
+			Version:      strconv.Itoa(payload.Version),
+			Nonce:        strconv.Itoa(payload.Nonce),
+			Amount:       payload.Amount,
+			GasPrice:     payload.GasPrice,
+			GasLimit:     payload.GasLimit,
+			Signature:    "0x" + strings.ToUpper(payload.Signature),
+			SenderPubKey: "0x" + strings.ToUpper(payload.PubKey),
+			ToAddr:       normalizeAddress(payload.ToAddr),
+			Code:         payload.Code,
+			Data:         payload.Data,
+		},
+		sender: sender,
+		toAddr: normalizeAddress(payload.ToAddr),
+		amount: amount,
+		fee:    fee,
+		nonce:  uint64(payload.Nonce),
+	}
+
+	result := &provider.CreateTxResult{TranID: id}
+	switch {
+	case t.toAddr == zeroAddress:
+		if payload.Code == "" {
+			return nil, newError(codeVerifyRejected, "Contract creation txn without code")
+		}
+		t.contract = contractAddress(sender, nonce)
+		t.toAddr = t.contract
+		s.contracts[t.contract] = &contract{code: payload.Code, init: payload.Data}
+		result.ContractAddress = t.contract
+		result.Info = "Contract Creation txn, sent to shard"
+	case s.contracts[t.toAddr] != nil:
+		result.Info = "Contract Txn, Shards Match of the sender and reciever"
+	default:
+		result.Info = "Non-contract txn, sent to shard"
+	}
+
+	s.txns[id] = t
+	s.pending = append(s.pending, t)
+	if s.autoMine {
+		s.mine()
+	}
+	return result, nil
+}
+
+func getTransaction(s *Server, params []json.RawMessage) (interface{}, *rpcError) {
+	hash, err := stringParam(params)
+	if err != nil {
+		return nil, err
+	}
+	t, ok := s.txns[normalizeAddress(hash)]
+	if !ok || !t.mined {
+		return nil, newError(codeDatabaseError, txnNotPresent)
+	}
+	return t.result, nil
+}
+
+func getPendingTxn(s *Server, params []json.RawMessage) (interface{}, *rpcError) {
+	hash, err := stringParam(params)
+	if err != nil {
+		return nil, err
+	}
+	t, ok := s.txns[normalizeAddress(hash)]
+	if !ok {
+		return nil, newError(codeDatabaseError, txnNotPresent)
+	}
+	if t.mined {
+		return &provider.PendingTxnStatus{Code: 0, Confirmed: true, Info: "Txn already processed and confirmed"}, nil
+	}
+	return &provider.PendingTxnStatus{Code: 3, Confirmed: false, Info: "Transaction valid but consensus not reached"}, nil
+}
+
+// getTransactionStatus reports pending transactions as dispatched and mined ones as confirmed.
+func getTransactionStatus(s *Server, params []json.RawMessage) (interface{}, *rpcError) {
+	hash, err := stringParam(params)
+	if err != nil {
+		return nil, err
+	}
+	t, ok := s.txns[normalizeAddress(hash)]
+	if !ok {
+		return nil, newError(codeDatabaseError, txnNotPresent)
+	}
+	status := &provider.TransactionStatus{
+		ID:         t.result.ID,
+		Amount:     t.result.Amount,
+		GasLimit:   t.result.GasLimit,
+		GasPrice:   t.result.GasPrice,
+		Nonce:      t.result.Nonce,
+		SenderAddr: t.sender,
+		ToAddr:     t.result.ToAddr,
+		Version:    t.result.Version,
+		Status:     1,
+	}
+	if t.mined {
+		status.ModificationState = 2
+		status.Status = 3
+		status.Success = t.result.Receipt.Success
+		status.EpochInserted = t.result.Receipt.EpochNum
+		status.EpochUpdated = t.result.Receipt.EpochNum
+	}
+	return status, nil
+}
+
+func getRecentTransactions(s *Server, params []json.RawMessage) (interface{}, *rpcError) {
+	hashes := []string{}
+	for i := len(s.blockTxns) - 1; i >= 0 && len(hashes) < 100; i-- {
+		for j := len(s.blockTxns[i]) - 1; j >= 0 && len(hashes) < 100; j-- {
+			hashes = append(hashes, s.blockTxns[i][j].result.ID)
+		}
+	}
+	return &provider.RecentTransactions{TxnHashes: hashes, Number: uint64(len(hashes))}, nil
+}
+
+func getNumTxBlocks(s *Server, params []json.RawMessage) (interface{}, *rpcError) {
+	return strconv.Itoa(len(s.blocks)), nil
+}
+
+func getNumTransactions(s *Server, params []json.RawMessage) (interface{}, *rpcError) {
+	return strconv.Itoa(s.numTransactions()), nil
+}
+
+func getLatestTxBlock(s *Server, params []json.RawMessage) (interface{}, *rpcError) {
+	return s.blocks[len(s.blocks)-1], nil
+}
+
+func getTxBlock(s *Server, params []json.RawMessage) (interface{}, *rpcError) {
+	blockNum, err := s.blockParam(params)
+	if err != nil {
+		return nil, err
+	}
+	return s.blocks[blockNum], nil
+}
+
+func getTransactionsForTxBlock(s *Server, params []json.RawMessage) (interface{}, *rpcError) {
+	blockNum, err := s.blockParam(params)
+	if err != nil {
+		return nil, err
+	}
+	txns := s.blockTxns[blockNum]
+	if len(txns) == 0 {
+		return nil, newError(codeMiscError, txBlockNoTransactions)
+	}
+	hashes := make([]string, 0, len(txns))
+	for _, t := range txns {
+		hashes = append(hashes, t.result.ID)
+	}
+	return [][]string{hashes}, nil
+}
+
+func getTxnBodiesForTxBlock(s *Server, params []json.RawMessage) (interface{}, *rpcError) {
+	blockNum, err := s.blockParam(params)
+	if err != nil {
+		return nil, err
+	}
+	txns := s.blockTxns[blockNum]
+	if len(txns) == 0 {
+		return nil, newError(codeMiscError, txBlockNoTransactions)
+	}
+	bodies := make([]*provider.TransactionResult, 0, len(txns))
+	for _, t := range txns {
+		bodies = append(bodies, t.result)
+	}
+	return bodies, nil
+}
+
+func getContractAddressFromTransactionID(s *Server, params []json.RawMessage) (interface{}, *rpcError) {
+	hash, err := stringParam(params)
+	if err != nil {
+		return nil, err
+	}
+	t, ok := s.txns[normalizeAddress(hash)]
+	if !ok || !t.mined {
+		return nil, newError(codeDatabaseError, txnNotPresent)
+	}
+	if t.contract == "" {
+		return nil, newError(codeInvalidParameter, "ID is not a contract txn")
+	}
+	return t.contract, nil
+}
+
+// deployedContract looks up a contract whose deployment was mined.
+func (s *Server) deployedContract(params []json.RawMessage) (*contract, *rpcError) {
+	address, err := stringParam(params)
+	if err != nil {
+		return nil, err
+	}
+	c, ok := s.contracts[normalizeAddress(address)]
+	if !ok || s.account(normalizeAddress(address), false) == nil {
+		return nil, newError(codeInvalidAddress, "Address not contract address")
+	}
+	return c, nil
+}
+
+func getSmartContractCode(s *Server, params []json.RawMessage) (interface{}, *rpcError) {
+	c, err := s.deployedContract(params)
+	if err != nil {
+		return nil, err
+	}
+	return map[string]string{"code": c.code}, nil
+}
+
+func getSmartContractInit(s *Server, params []json.RawMessage) (interface{}, *rpcError) {
+	c, err := s.deployedContract(params)
+	if err != nil {
+		return nil, err
+	}
+	init := []interface{}{}
+	if c.init != "" {
+		if err := json.Unmarshal([]byte(c.init), &init); err != nil {
+			return nil, newError(codeMiscError, "Invalid init data: %s", err)
+		}
+	}
+	return init, nil
+}
@@ -0,0 +1,316 @@
+/*
+ * Copyright (C) 2019 Zilliqa
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+// Package zilliqatest provides an in-process Zilliqa node for tests that must not depend on
+// a public network. The node speaks the Zilliqa JSON-RPC dialect over an httptest.Server, keeps
+// balances and nonces of accounts, verifies the Schnorr signature of every transaction and only
+// mines a Tx block when asked to.
+//
+//	server := zilliqatest.NewServer()
+//	defer server.Close()
+//	server.Fund(address, "1000000000000000")
+//	p := provider.NewProvider(server.URL)
+//	... sign and send a transaction with p ...
+//	server.Mine()
+//
+// Scilla is not executed: deploying a contract records its code and init parameters, and a call
+// to a contract only transfers the amount.
+package zilliqatest
+
+import (
+	"encoding/binary"
+	"fmt"
+	"github.com/Zilliqa/gozilliqa-sdk/keytools"
+	"github.com/Zilliqa/gozilliqa-sdk/provider"
+	go_schnorr "github.com/Zilliqa/gozilliqa-sdk/schnorr"
+	"github.com/Zilliqa/gozilliqa-sdk/transaction"
+	"github.com/Zilliqa/gozilliqa-sdk/util"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const zeroAddress = "0000000000000000000000000000000000000000"
+
+type Server struct {
+	*httptest.Server
+
+	mu          sync.Mutex
+	chainID     int
+	minGasPrice *big.Int
+	autoMine    bool
+	accounts    map[string]*account
+	contracts   map[string]*contract
+	txns        map[string]*txn
+	pending     []*txn
+	blocks      []*provider.TxBlock
+	blockTxns   [][]*txn
+}
+
+type account struct {
+	balance *big.Int
+	nonce   uint64
+}
+
+type contract struct {
+	code string
+	init string
+}
+
+type txn struct {
+	result   *provider.TransactionResult
+	sender   string
+	toAddr   string
+	contract string
+	amount   *big.Int
+	fee      *big.Int
+	nonce    uint64
+	mined    bool
+}
+
+// Option configures a Server created by NewServer.
+type Option func(s *Server)
+
+// WithChainID sets the chain id the server reports and requires in the version of transactions.
+// The default is 333, the id of the developer testnet.
+func WithChainID(chainID int) Option {
+	return func(s *Server) {
+		s.chainID = chainID
+	}
+}
+
+// WithMinimumGasPrice sets the minimum gas price in Qa. The default is 1000000000.
+func WithMinimumGasPrice(gasPrice string) Option {
+	return func(s *Server) {
+		if price, ok := new(big.Int).SetString(gasPrice, 10); ok {
+			s.minGasPrice = price
+		}
+	}
+}
+
+// WithAutoMine makes the server mine a Tx block for every accepted transaction, so that it is
+// confirmed as soon as CreateTransaction returns.
+func WithAutoMine() Option {
+	return func(s *Server) {
+		s.autoMine = true
+	}
+}
+
+// NewServer starts a server with an empty genesis Tx block. The caller should call Close when finished.
+func NewServer(opts ...Option) *Server {
+	s := &Server{
+		chainID:     333,
+		minGasPrice: big.NewInt(1000000000),
+		accounts:    make(map[string]*account),
+		contracts:   make(map[string]*contract),
+		txns:        make(map[string]*txn),
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	s.mine()
+	s.Server = httptest.NewServer(http.HandlerFunc(s.serveHTTP))
+	return s
+}
+
+// ChainID returns the chain id of the server.
+func (s *Server) ChainID() int {
+	return s.chainID
+}
+
+// Provider returns a provider connected to the server.
+func (s *Server) Provider(opts ...provider.Option) *provider.Provider {
+	return provider.NewProviderWithOptions(s.URL, opts...)
+}
+
+// Fund adds amount Qa to the balance of address, creating the account if necessary.
+// Addresses may be given with or without 0x and in any case.
+func (s *Server) Fund(address string, amount string) {
+	value, ok := new(big.Int).SetString(amount, 10)
+	if !ok {
+		panic("zilliqatest: invalid amount " + amount)
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	a := s.account(normalizeAddress(address), true)
+	a.balance.Add(a.balance, value)
+}
+
+// Balance returns the balance and the nonce of address as of the latest Tx block.
+func (s *Server) Balance(address string) (balance *big.Int, nonce uint64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	a := s.account(normalizeAddress(address), false)
+	if a == nil {
+		return big.NewInt(0), 0
+	}
+	return new(big.Int).Set(a.balance), a.nonce
+}
+
+// Mine puts all pending transactions into a new Tx block and returns its number.
+func (s *Server) Mine() uint64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.mine()
+}
+
+// BlockNum returns the number of the latest Tx block.
+func (s *Server) BlockNum() uint64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return uint64(len(s.blocks) - 1)
+}
+
+// SendTransfer sends a transfer of amount Qa to toAddr from a new account, which is funded
+// with just enough to pay for it, and returns the ID of the transaction. The transaction is
+// pending until the next Mine, unless the server mines automatically.
+func (s *Server) SendTransfer(toAddr, amount string) (string, error) {
+	privateKey, err := keytools.GeneratePrivateKey()
+	if err != nil {
+		return "", err
+	}
+	publicKey := keytools.GetPublicKeyFromPrivateKey(privateKey[:], true)
+	tx := &transaction.Transaction{
+		Version:      strconv.Itoa(util.Pack(s.chainID, 1)),
+		Nonce:        "1",
+		Amount:       amount,
+		GasPrice:     s.minGasPrice.String(),
+		GasLimit:     "50",
+		SenderPubKey: util.EncodeHex(publicKey),
+		ToAddr:       toAddr,
+	}
+	typed, err := tx.Typed()
+	if err != nil {
+		return "", err
+	}
+	fee := new(big.Int).Mul(typed.GasPrice, new(big.Int).SetUint64(typed.GasLimit))
+	s.Fund(keytools.GetAddressFromPublic(publicKey), new(big.Int).Add(typed.Amount, fee).String())
+
+	message, err := tx.Bytes()
+	if err != nil {
+		return "", err
+	}
+	random, err := keytools.GenerateRandomBytes(32)
+	if err != nil {
+		return "", err
+	}
+	r, sig, err := go_schnorr.TrySign(privateKey[:], publicKey, message, random)
+	if err != nil {
+		return "", err
+	}
+	tx.Signature = fmt.Sprintf("%064s%064s", util.EncodeHex(r), util.EncodeHex(sig))
+
+	payload, err := tx.Payload()
+	if err != nil {
+		return "", err
+	}
+	created, err := s.Provider().CreateTransactionTyped(payload)
+	if err != nil {
+		return "", err
+	}
+	return created.TranID, nil
+}
+
+func (s *Server) account(address string, create bool) *account {
+	a, ok := s.accounts[address]
+	if !ok && create {
+		a = &account{balance: new(big.Int)}
+		s.accounts[address] = a
+	}
+	return a
+}
+
+func (s *Server) mine() uint64 {
+	blockNum := uint64(len(s.blocks))
+	gasUsed := new(big.Int)
+	hashes := make([]string, 0, len(s.pending))
+	for _, t := range s.pending {
+		sender := s.accounts[t.sender]
+		sender.balance.Sub(sender.balance, new(big.Int).Add(t.amount, t.fee))
+		sender.nonce = t.nonce
+		receiver := s.account(t.toAddr, true)
+		receiver.balance.Add(receiver.balance, t.amount)
+
+		gasLimit, _ := new(big.Int).SetString(t.result.GasLimit, 10)
+		gasUsed.Add(gasUsed, gasLimit)
+		t.result.Receipt = provider.Receipt{
+			Success:       true,
+			CumulativeGas: t.result.GasLimit,
+			EpochNum:      strconv.FormatUint(blockNum, 10),
+		}
+		t.mined = true
+		hashes = append(hashes, t.result.ID)
+	}
+
+	prevHash := strings.Repeat("0", 64)
+	if blockNum > 0 {
+		prevHash = s.blocks[blockNum-1].Body.BlockHash
+	}
+	blockHash := util.EncodeHex(util.Sha256([]byte(prevHash + strings.Join(hashes, ""))))
+	block := &provider.TxBlock{
+		Header: &provider.Header{
+			BlockNum:      strconv.FormatUint(blockNum, 10),
+			DSBlockNum:    strconv.FormatUint(blockNum/100, 10),
+			GasLimit:      "2000000",
+			GasUsed:       gasUsed.String(),
+			NumTxns:       uint32(len(hashes)),
+			PrevBlockHash: prevHash,
+			Rewards:       "0",
+			Timestamp:     strconv.FormatInt(time.Now().UnixNano()/1000, 10),
+			Version:       1,
+		},
+		Body: &provider.Body{
+			BlockHash: blockHash,
+		},
+	}
+	if len(hashes) > 0 {
+		block.Header.NumMicroBlocks = 1
+		block.Body.MicroBlockInfos = []*provider.MicroBlockInfo{{
+			MicroBlockHash:        util.EncodeHex(util.Sha256([]byte(strings.Join(hashes, "")))),
+			MicroBlockTxnRootHash: util.EncodeHex(util.Sha256([]byte(blockHash))),
+		}}
+	}
+
+	s.blocks = append(s.blocks, block)
+	s.blockTxns = append(s.blockTxns, s.pending)
+	s.pending = nil
+	return blockNum
+}
+
+// normalizeAddress turns a hex address in any case, with or without 0x, into lower case without 0x.
+func normalizeAddress(address string) string {
+	return strings.TrimPrefix(strings.ToLower(address), "0x")
+}
+
+// contractAddress derives the address of a contract deployed by sender when its nonce was nonce,
+// the same way the Zilliqa node does.
+func contractAddress(sender string, nonce uint64) string {
+	data := make([]byte, 0, 28)
+	data = append(data, util.DecodeHex(sender)...)
+	n := make([]byte, 8)
+	binary.BigEndian.PutUint64(n, nonce)
+	data = append(data, n...)
+	return util.EncodeHex(util.Sha256(data))[24:]
+}
+
+// senderAddress returns the address of the owner of a hex public key.
+func senderAddress(pubKey string) string {
+	return keytools.GetAddressFromPublic(util.DecodeHex(pubKey))
+}
@@ -0,0 +1,197 @@
+/*
+ * Copyright (C) 2019 Zilliqa
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+package zilliqatest_test
+
+import (
+	"errors"
+	"github.com/Zilliqa/gozilliqa-sdk/account"
+	"github.com/Zilliqa/gozilliqa-sdk/keytools"
+	"github.com/Zilliqa/gozilliqa-sdk/provider"
+	"github.com/Zilliqa/gozilliqa-sdk/transaction"
+	"github.com/Zilliqa/gozilliqa-sdk/util"
+	. "github.com/Zilliqa/gozilliqa-sdk/zilliqatest"
+	"github.com/stretchr/testify/assert"
+	"math/big"
+	"strconv"
+	"testing"
+)
+
+const (
+	testPrivateKey = "e19d05c5452598e24caad4a0d85a49146f7be089515c905ae6a19e8a578a6930"
+	testToAddr     = "4BAF5faDA8e5Db92C3d3242618c5B47133AE003C"
+)
+
+func signedTransfer(t *testing.T, server *Server, amount string) *transaction.Transaction {
+	wallet := account.NewWallet()
+	wallet.AddByPrivateKey(testPrivateKey)
+	tx := &transaction.Transaction{
+		Version:  strconv.FormatInt(int64(util.Pack(server.ChainID(), 1)), 10),
+		ToAddr:   testToAddr,
+		Amount:   amount,
+		GasPrice: "1000000000",
+		GasLimit: "50",
+	}
+	err := wallet.Sign(tx, server.Provider())
+	assert.Nil(t, err, err)
+	return tx
+}
+
+func payloadOf(t *testing.T, tx *transaction.Transaction) provider.TransactionPayload {
+	payload, err := tx.Payload()
+	assert.Nil(t, err, err)
+	return payload
+}
+
+func TestServer_Transfer(t *testing.T) {
+	server := NewServer()
+	defer server.Close()
+	sender := keytools.GetAddressFromPrivateKey(util.DecodeHex(testPrivateKey))
+	server.Fund(sender, "1000000000000")
+	p := server.Provider()
+
+	tx := signedTransfer(t, server, "1000")
+	created, err := p.CreateTransactionTyped(payloadOf(t, tx))
+	assert.Nil(t, err, err)
+	assert.Equal(t, "Non-contract txn, sent to shard", created.Info)
+
+	_, err = p.GetTransactionTyped(created.TranID)
+	assert.NotNil(t, err)
+	pending, err := p.GetPendingTxnTyped(created.TranID)
+	assert.Nil(t, err, err)
+	assert.False(t, pending.Confirmed)
+
+	assert.Equal(t, uint64(1), server.Mine())
+	result, err := p.GetTransactionTyped(created.TranID)
+	assert.Nil(t, err, err)
+	assert.True(t, result.Receipt.Success)
+	assert.Equal(t, "1", result.Receipt.EpochNum)
+
+	hashes, err := p.GetTransactionsForTxBlockTyped("1")
+	assert.Nil(t, err, err)
+	assert.Equal(t, [][]string{{created.TranID}}, hashes)
+	block, err := p.GetTxBlockTyped("1")
+	assert.Nil(t, err, err)
+	assert.Equal(t, uint32(1), block.Header.NumTxns)
+	_, err = p.GetTransactionsForTxBlockTyped("0")
+	assert.True(t, errors.Is(err, provider.EmptyBlock), err)
+
+	balance, nonce := server.Balance(sender)
+	assert.Equal(t, big.NewInt(1000000000000-1000-50*1000000000), balance)
+	assert.Equal(t, uint64(1), nonce)
+	received, err := p.GetBalanceTyped(testToAddr)
+	assert.Nil(t, err, err)
+	assert.Equal(t, big.NewInt(1000), received.Balance)
+}
+
+func TestServer_CreateTransactionRejected(t *testing.T) {
+	server := NewServer()
+	defer server.Close()
+	sender := keytools.GetAddressFromPrivateKey(util.DecodeHex(testPrivateKey))
+	p := server.Provider()
+
+	// the sender account does not exist yet, so signing starts at nonce 1
+	tx := signedTransfer(t, server, "1000")
+	rsp, err := p.CreateTransaction(payloadOf(t, tx))
+	assert.Nil(t, err, err)
+	assert.True(t, errors.Is(provider.ResponseError(rsp, err), provider.ErrInsufficientBalance), rsp.Error)
+	assert.Equal(t, "The sender of the txn has no balance", rsp.Error.Message)
+
+	server.Fund(sender, "100000000000")
+	rsp, err = p.CreateTransaction(payloadOf(t, tx))
+	assert.Nil(t, provider.ResponseError(rsp, err))
+	rsp, _ = p.CreateTransaction(payloadOf(t, tx))
+	assert.Equal(t, "Txn already present", rsp.Error.Message)
+
+	payload := payloadOf(t, signedTransfer(t, server, "2000"))
+	payload.Nonce = 5
+	rsp, _ = p.CreateTransaction(payload)
+	assert.Equal(t, "Unable to verify transaction", rsp.Error.Message)
+
+	for message, invalid := range map[string]func(*provider.TransactionPayload){
+		`Invalid amount ""`:     func(payload *provider.TransactionPayload) { payload.Amount = "" },
+		`Invalid gas price "x"`: func(payload *provider.TransactionPayload) { payload.GasPrice = "x" },
+		`Invalid gas limit "-"`: func(payload *provider.TransactionPayload) { payload.GasLimit = "-" },
+	} {
+		payload := payloadOf(t, signedTransfer(t, server, "2000"))
+		invalid(&payload)
+		rsp, _ = p.CreateTransaction(payload)
+		assert.Equal(t, -8, rsp.Error.Code)
+		assert.Equal(t, message, rsp.Error.Message)
+	}
+
+	// the nonce of the first transaction is still pending
+	tx = signedTransfer(t, server, "1001")
+	rsp, err = p.CreateTransaction(payloadOf(t, tx))
+	assert.True(t, errors.Is(provider.ResponseError(rsp, err), provider.ErrNonceTooLow), rsp.Error)
+
+	tx = signedTransfer(t, server, "1000")
+	tx.Nonce = "2"
+	wallet := account.NewWallet()
+	wallet.AddByPrivateKey(testPrivateKey)
+	tx.Amount = "100000000000"
+	assert.Nil(t, wallet.Sign(tx, p))
+	rsp, err = p.CreateTransaction(payloadOf(t, tx))
+	var rpcErr *provider.RPCError
+	assert.True(t, errors.As(provider.ResponseError(rsp, err), &rpcErr))
+	assert.Equal(t, "Insufficient Balance", rpcErr.Message)
+}
+
+func TestServer_AutoMine(t *testing.T) {
+	server := NewServer(WithAutoMine(), WithChainID(1))
+	defer server.Close()
+	sender := keytools.GetAddressFromPrivateKey(util.DecodeHex(testPrivateKey))
+	server.Fund("0x"+sender, "1000000000000")
+
+	tx := signedTransfer(t, server, "1")
+	created, err := server.Provider().CreateTransactionTyped(payloadOf(t, tx))
+	assert.Nil(t, err, err)
+	assert.True(t, tx.TrackTx(created.TranID, server.Provider()))
+	assert.Equal(t, transaction.Confirmed, tx.Status)
+	assert.Equal(t, uint64(1), server.BlockNum())
+}
+
+func TestServer_SendTransfer(t *testing.T) {
+	server := NewServer()
+	defer server.Close()
+	p := server.Provider()
+
+	hash, err := server.SendTransfer(testToAddr, "1000")
+	assert.Nil(t, err, err)
+	server.Mine()
+	result, err := p.GetTransactionTyped(hash)
+	assert.Nil(t, err, err)
+	assert.True(t, result.Receipt.Success)
+	received, err := p.GetBalanceTyped(testToAddr)
+	assert.Nil(t, err, err)
+	assert.Equal(t, big.NewInt(1000), received.Balance)
+
+	// the sender is funded with exactly the amount and the fee
+	balance, nonce := server.Balance(keytools.GetAddressFromPublic(util.DecodeHex(result.SenderPubKey)))
+	assert.Equal(t, big.NewInt(0), balance)
+	assert.Equal(t, uint64(1), nonce)
+}
+
+func TestServer_Batch(t *testing.T) {
+	server := NewServer()
+	defer server.Close()
+
+	results, err := server.Provider().NewBatch().GetTxBlock("0").GetTxBlock("1").Send()
+	assert.Nil(t, err, err)
+	assert.Nil(t, results[0].Err)
+	assert.Equal(t, "0", results[0].Result.(*provider.TxBlock).Header.BlockNum)
+	assert.NotNil(t, results[1].Err)
+}